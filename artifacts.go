@@ -0,0 +1,477 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/CRASH-Tech/dhcp-operator/cmd/kubernetes/api/v1alpha1"
+	log "github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+const (
+	artifactSyncPeriod     = 30 * time.Second
+	artifactChecksumPrefix = "sha256:"
+)
+
+type cachedArtifact struct {
+	file     string
+	checksum string
+	size     int64
+	modTime  time.Time
+	source   string
+}
+
+// artifacts indexes the cached boot artifacts by the path they are served at.
+var artifacts struct {
+	sync.RWMutex
+	items map[string]cachedArtifact
+}
+
+// runArtifactSync keeps the boot artifacts cached on disk until ctx is
+// cancelled. It runs in the worker, so in leader mode only the leader keeps
+// a cache, while in active-active mode every replica keeps its own, as every
+// replica serves them.
+func runArtifactSync(ctx context.Context) {
+	if !config.Artifacts.Enabled {
+		return
+	}
+
+	err := os.MkdirAll(config.Artifacts.CacheDir, 0o755)
+	if err != nil {
+		log.Error(err)
+
+		return
+	}
+
+	ticker := time.NewTicker(artifactSyncPeriod)
+	defer ticker.Stop()
+
+	for {
+		artifactSync(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func artifactSync(ctx context.Context) {
+	log.Debug("Start boot artifact sync...")
+
+	items, err := kClient.V1alpha1().BootArtifact().GetAll()
+	if err != nil {
+		log.Error(err)
+
+		return
+	}
+
+	seen := make(map[string]bool)
+	for _, artifact := range items {
+		artifactPath := cleanArtifactPath(artifact.Spec.Path)
+		seen[artifactPath] = true
+
+		cached, err := syncArtifact(ctx, artifact, artifactPath)
+		if err != nil {
+			log.Errorf("Cannot sync boot artifact %s: %s", artifact.Name, err)
+		}
+
+		if isPrimaryReplica() {
+			updateArtifactStatus(artifact, cached, err)
+		}
+	}
+
+	artifacts.Lock()
+	defer artifacts.Unlock()
+
+	for artifactPath, cached := range artifacts.items {
+		if seen[artifactPath] {
+			continue
+		}
+
+		log.Infof("Remove boot artifact: %s", artifactPath)
+		delete(artifacts.items, artifactPath)
+		if strings.HasPrefix(cached.file, config.Artifacts.CacheDir) && !isArtifactFileUsed(cached.file) {
+			os.Remove(cached.file)
+		}
+	}
+}
+
+// isArtifactFileUsed reports whether a cached artifact still points at the
+// file, as the file is kept when only the path of an artifact changed.
+// artifacts must be locked.
+func isArtifactFileUsed(file string) bool {
+	for _, cached := range artifacts.items {
+		if cached.file == file {
+			return true
+		}
+	}
+
+	return false
+}
+
+// syncArtifact makes sure the artifact is cached and matches its checksum.
+// Sources are fetched again only when the spec changed.
+func syncArtifact(ctx context.Context, artifact v1alpha1.BootArtifact, artifactPath string) (cachedArtifact, error) {
+	source := artifactSource(artifact)
+
+	artifacts.RLock()
+	cached, found := artifacts.items[artifactPath]
+	artifacts.RUnlock()
+
+	var err error
+	switch {
+	case artifact.Spec.PersistentVolumeClaim != nil:
+		cached, err = syncVolumeArtifact(artifact, cached, found && cached.source == source)
+	case artifact.Spec.ConfigMap != nil:
+		cached, err = syncConfigMapArtifact(artifact)
+	case artifact.Spec.URL != "":
+		if found && cached.source == source {
+			_, statErr := os.Stat(cached.file)
+			if statErr == nil {
+				return cached, nil
+			}
+		}
+
+		cached, err = downloadArtifact(ctx, artifact)
+	default:
+		err = errors.New("no source set")
+	}
+	if err != nil {
+		return cachedArtifact{}, err
+	}
+
+	cached.source = source
+
+	artifacts.Lock()
+	defer artifacts.Unlock()
+
+	if artifacts.items == nil {
+		artifacts.items = make(map[string]cachedArtifact)
+	}
+	artifacts.items[artifactPath] = cached
+
+	return cached, nil
+}
+
+// syncVolumeArtifact serves the file in place from the mounted volume, the
+// checksum is calculated again only when the file changed.
+func syncVolumeArtifact(artifact v1alpha1.BootArtifact, cached cachedArtifact, unchanged bool) (cachedArtifact, error) {
+	file, err := volumeArtifactFile(artifact)
+	if err != nil {
+		return cachedArtifact{}, err
+	}
+
+	info, err := os.Stat(file)
+	if err != nil {
+		return cachedArtifact{}, err
+	}
+
+	if unchanged && cached.size == info.Size() && cached.modTime.Equal(info.ModTime()) {
+		return cached, nil
+	}
+
+	checksum, err := hashFile(file)
+	if err != nil {
+		return cachedArtifact{}, err
+	}
+
+	err = verifyChecksum(artifact.Spec.Checksum, checksum)
+	if err != nil {
+		return cachedArtifact{}, err
+	}
+
+	return cachedArtifact{
+		file:     file,
+		checksum: checksum,
+		size:     info.Size(),
+		modTime:  info.ModTime(),
+	}, nil
+}
+
+// volumeArtifactFile resolves the file of a volume artifact and makes sure it
+// stays inside the volume of the claim, also through symlinks. Volumes are
+// mounted at VolumesDir/<claim>, in namespaced scope at
+// VolumesDir/<namespace>/<claim>, so a tenant only reaches its own claims.
+func volumeArtifactFile(artifact v1alpha1.BootArtifact) (string, error) {
+	pvc := artifact.Spec.PersistentVolumeClaim
+	if errs := validation.IsDNS1123Subdomain(pvc.ClaimName); len(errs) > 0 {
+		return "", fmt.Errorf("invalid claim name %q: %s", pvc.ClaimName, strings.Join(errs, ", "))
+	}
+
+	root := filepath.Join(config.Artifacts.VolumesDir, pvc.ClaimName)
+	if config.Scope == scopeNamespaced {
+		root = filepath.Join(config.Artifacts.VolumesDir, artifact.Namespace, pvc.ClaimName)
+	}
+
+	root, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		return "", err
+	}
+
+	file, err := filepath.EvalSymlinks(filepath.Join(root, filepath.FromSlash(cleanArtifactPath(pvc.Path))))
+	if err != nil {
+		return "", err
+	}
+
+	rel, err := filepath.Rel(root, file)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("path %q is outside of claim %s", pvc.Path, pvc.ClaimName)
+	}
+
+	return file, nil
+}
+
+func syncConfigMapArtifact(artifact v1alpha1.BootArtifact) (cachedArtifact, error) {
+	cm := artifact.Spec.ConfigMap
+	cmNamespace := cm.Namespace
+	if cmNamespace == "" || config.Scope == scopeNamespaced {
+		cmNamespace = artifact.Namespace
+	}
+	if cmNamespace == "" {
+		cmNamespace = namespace
+	}
+
+	data, err := kClient.V1alpha1().BootArtifact().GetConfigMapData(cmNamespace, cm.Name, cm.Key)
+	if err != nil {
+		return cachedArtifact{}, err
+	}
+
+	sum := sha256.Sum256(data)
+	checksum := hex.EncodeToString(sum[:])
+
+	err = verifyChecksum(artifact.Spec.Checksum, checksum)
+	if err != nil {
+		return cachedArtifact{}, err
+	}
+
+	file := artifactCacheFile(artifact)
+	info, err := os.Stat(file)
+	if err == nil && info.Size() == int64(len(data)) {
+		existing, err := hashFile(file)
+		if err == nil && existing == checksum {
+			return cachedArtifact{file: file, checksum: checksum, size: info.Size(), modTime: info.ModTime()}, nil
+		}
+	}
+
+	err = writeArtifactFile(file, bytes.NewReader(data))
+	if err != nil {
+		return cachedArtifact{}, err
+	}
+
+	return statArtifact(file, checksum)
+}
+
+func downloadArtifact(ctx context.Context, artifact v1alpha1.BootArtifact) (cachedArtifact, error) {
+	log.Infof("Download boot artifact %s from %s", artifact.Name, artifact.Spec.URL)
+
+	ctx, cancel := context.WithTimeout(ctx, config.Artifacts.DownloadTimeout)
+	defer cancel()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, artifact.Spec.URL, nil)
+	if err != nil {
+		return cachedArtifact{}, err
+	}
+
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return cachedArtifact{}, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return cachedArtifact{}, fmt.Errorf("download failed: %s", response.Status)
+	}
+
+	hash := sha256.New()
+	file := artifactCacheFile(artifact)
+
+	err = writeArtifactFile(file, io.TeeReader(response.Body, hash), func() error {
+		return verifyChecksum(artifact.Spec.Checksum, hex.EncodeToString(hash.Sum(nil)))
+	})
+	if err != nil {
+		return cachedArtifact{}, err
+	}
+
+	return statArtifact(file, hex.EncodeToString(hash.Sum(nil)))
+}
+
+// writeArtifactFile writes the file atomically, the checks run before the
+// file replaces the previous version.
+func writeArtifactFile(file string, data io.Reader, checks ...func() error) error {
+	tmp, err := os.CreateTemp(filepath.Dir(file), filepath.Base(file)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = io.Copy(tmp, data)
+	if err != nil {
+		tmp.Close()
+
+		return err
+	}
+
+	err = tmp.Close()
+	if err != nil {
+		return err
+	}
+
+	for _, check := range checks {
+		err = check()
+		if err != nil {
+			return err
+		}
+	}
+
+	return os.Rename(tmp.Name(), file)
+}
+
+func statArtifact(file, checksum string) (cachedArtifact, error) {
+	info, err := os.Stat(file)
+	if err != nil {
+		return cachedArtifact{}, err
+	}
+
+	return cachedArtifact{
+		file:     file,
+		checksum: checksum,
+		size:     info.Size(),
+		modTime:  info.ModTime(),
+	}, nil
+}
+
+func updateArtifactStatus(artifact v1alpha1.BootArtifact, cached cachedArtifact, syncErr error) {
+	status := v1alpha1.BootArtifactStatus{
+		ObservedGeneration: artifact.Generation,
+		Phase:              v1alpha1.BootArtifactReady,
+		Checksum:           artifactChecksumPrefix + cached.checksum,
+		Size:               cached.size,
+		LastSync:           artifact.Status.LastSync,
+	}
+	if syncErr != nil {
+		status.Phase = v1alpha1.BootArtifactFailed
+		status.Checksum = ""
+		status.Message = syncErr.Error()
+	}
+
+	current := artifact.Status
+	current.LastSync = status.LastSync
+	if current == status {
+		return
+	}
+
+	now := metav1.Now()
+	status.LastSync = &now
+	artifact.Status = status
+
+	_, err := kClient.V1alpha1().BootArtifact().UpdateStatus(artifact)
+	if err != nil {
+		log.Error(err)
+	}
+}
+
+// openArtifact opens the cached artifact served at the path.
+func openArtifact(artifactPath string) (*os.File, cachedArtifact, bool) {
+	artifacts.RLock()
+	cached, found := artifacts.items[cleanArtifactPath(artifactPath)]
+	artifacts.RUnlock()
+
+	if !found {
+		return nil, cachedArtifact{}, false
+	}
+
+	file, err := os.Open(cached.file)
+	if err != nil {
+		log.Error(err)
+
+		return nil, cachedArtifact{}, false
+	}
+
+	return file, cached, true
+}
+
+// artifactHandler serves cached artifacts with Range and ETag support and
+// falls back to the files baked into the image.
+func artifactHandler(fallback http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		file, cached, found := openArtifact(r.URL.Path)
+		if !found {
+			fallback.ServeHTTP(w, r)
+
+			return
+		}
+		defer file.Close()
+
+		w.Header().Set("ETag", `"`+cached.checksum+`"`)
+		http.ServeContent(w, r, path.Base(r.URL.Path), cached.modTime, file)
+	})
+}
+
+func artifactSource(artifact v1alpha1.BootArtifact) string {
+	source := artifact.Spec.URL
+	if cm := artifact.Spec.ConfigMap; cm != nil {
+		source = fmt.Sprintf("configmap:%s/%s/%s", cm.Namespace, cm.Name, cm.Key)
+	}
+	if pvc := artifact.Spec.PersistentVolumeClaim; pvc != nil {
+		source = fmt.Sprintf("pvc:%s/%s", pvc.ClaimName, pvc.Path)
+	}
+
+	return source + "#" + artifact.Spec.Checksum
+}
+
+func artifactCacheFile(artifact v1alpha1.BootArtifact) string {
+	sum := sha256.Sum256([]byte(artifact.Namespace + "/" + artifact.Name))
+
+	return filepath.Join(config.Artifacts.CacheDir, hex.EncodeToString(sum[:]))
+}
+
+func cleanArtifactPath(artifactPath string) string {
+	return strings.TrimPrefix(path.Clean("/"+artifactPath), "/")
+}
+
+func hashFile(file string) (string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	hash := sha256.New()
+	_, err = io.Copy(hash, f)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// verifyChecksum compares the sha256 of the content with the expected one,
+// given as hex with an optional "sha256:" prefix.
+func verifyChecksum(expected, actual string) error {
+	if expected == "" {
+		return nil
+	}
+
+	expected = strings.ToLower(strings.TrimPrefix(expected, artifactChecksumPrefix))
+	if expected != actual {
+		return fmt.Errorf("checksum mismatch: expected %s, got %s", expected, actual)
+	}
+
+	return nil
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"net/http"
+	"path"
+	"sort"
+
+	"github.com/CRASH-Tech/dhcp-operator/cmd/kubernetes"
+	"github.com/CRASH-Tech/dhcp-operator/cmd/kubernetes/api/v1alpha1"
+	log "github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// bootHandler serves the PXE config bound to the calling host, which is
+// identified by the mac or uuid query parameters or by its IP.
+func bootHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	log.Debugf("Request boot config: mac=%s uuid=%s ip=%s", query.Get("mac"), query.Get("uuid"), remoteIP(r))
+
+	if !requireSignedURL(w, r, path.Base(r.URL.Path)) {
+		return
+	}
+
+	lease, found, err := requestLease(r)
+	if err != nil {
+		log.Error(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte("Internal error!"))
+
+		return
+	}
+
+	if !found {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("Unknown host!"))
+
+		return
+	}
+
+	pxe, found, err := resolveBootPXE(lease)
+	if err != nil {
+		log.Error(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte("Internal error!"))
+
+		return
+	}
+
+	if !found {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("No boot config!"))
+
+		return
+	}
+
+	setRequestConfig(r, pxe.Name)
+	log.Debugf("Serve boot config %s for lease %s", pxe.Name, lease.Name)
+	writePXE(w, r, pxe, lease, true)
+}
+
+// resolveBootPXE finds the PXE config of a lease: the one referenced by the
+// lease, then the first one whose selector matches the lease labels and
+// finally the pool default.
+func resolveBootPXE(lease v1alpha1.Lease) (v1alpha1.PXE, bool, error) {
+	if lease.Spec.PXE != "" {
+		return getBootPXE(lease.Namespace, lease.Spec.PXE)
+	}
+
+	pxes, err := kClient.V1alpha1().PXE().GetAll()
+	if err != nil {
+		return v1alpha1.PXE{}, false, err
+	}
+
+	sort.Slice(pxes, func(i, j int) bool {
+		return pxes[i].Name < pxes[j].Name
+	})
+
+	for _, pxe := range pxes {
+		if pxe.Namespace != lease.Namespace || pxe.Spec.Selector == nil {
+			continue
+		}
+
+		selector, err := metav1.LabelSelectorAsSelector(pxe.Spec.Selector)
+		if err != nil {
+			log.Errorf("Invalid selector in PXE %s: %s", pxe.Name, err)
+
+			continue
+		}
+
+		if !selector.Empty() && selector.Matches(labels.Set(lease.Labels)) {
+			return pxe, true, nil
+		}
+	}
+
+	pool, err := kClient.V1alpha1().Pool().GetCached(lease.Namespace, lease.Spec.Pool)
+	if kubernetes.IsNotFound(err) {
+		return v1alpha1.PXE{}, false, nil
+	}
+	if err != nil {
+		return v1alpha1.PXE{}, false, err
+	}
+
+	if pool.Spec.PXE != "" {
+		return getBootPXE(lease.Namespace, pool.Spec.PXE)
+	}
+
+	return v1alpha1.PXE{}, false, nil
+}
+
+func getBootPXE(namespace, name string) (v1alpha1.PXE, bool, error) {
+	pxe, err := kClient.V1alpha1().PXE().Get(namespace, name)
+	if kubernetes.IsNotFound(err) {
+		return v1alpha1.PXE{}, false, nil
+	}
+	if err != nil {
+		return v1alpha1.PXE{}, false, err
+	}
+
+	return pxe, true, nil
+}
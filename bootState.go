@@ -0,0 +1,121 @@
+package main
+
+import (
+	"net"
+	"net/http"
+	"net/url"
+
+	"github.com/CRASH-Tech/dhcp-operator/cmd/kubernetes/api/v1alpha1"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const eventBootOnceFinished = "BootOnceFinished"
+
+// writePXE answers a boot request. Hosts in local boot get a script booting
+// from disk, hosts booting once are switched to local boot once the script
+// was served, or on the callback when the PXE config asks for it.
+func writePXE(w http.ResponseWriter, r *http.Request, pxe v1alpha1.PXE, lease v1alpha1.Lease, found bool) {
+	if found && lease.Spec.Boot == v1alpha1.LeaseBootLocalboot {
+		log.Debugf("Lease %s is in local boot", lease.Name)
+		w.Header().Set("Content-Type", pxeContentType(pxe))
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(localBootScript(pxe.Spec.Format)))
+
+		return
+	}
+
+	var callbackURL string
+	if found {
+		scheme := "http"
+		if r.TLS != nil {
+			scheme = "https"
+		}
+
+		callbackURL = (&url.URL{
+			Scheme: scheme,
+			Host:   r.Host,
+			Path:   "/boot/done",
+		}).String()
+	}
+
+	data, err := renderPXE(pxe, lease, r.URL.Query(), callbackURL)
+	if err != nil {
+		log.Errorf("Cannot render PXE config %s: %s", pxe.Name, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte("Internal error!"))
+
+		return
+	}
+
+	w.Header().Set("Content-Type", pxeContentType(pxe))
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(data))
+
+	// Only the host itself ends its boot once, the lease may have been picked
+	// by the mac or uuid parameters of another host.
+	if found && lease.Spec.Boot == v1alpha1.LeaseBootOnce && !pxe.Spec.Callback && isLeaseCaller(lease, r) {
+		finishBootOnce(lease)
+	}
+}
+
+func isLeaseCaller(lease v1alpha1.Lease, r *http.Request) bool {
+	ip := net.ParseIP(lease.Spec.Ip)
+
+	return ip != nil && ip.Equal(remoteIP(r))
+}
+
+// bootDoneHandler is called by a host at the end of the provisioning to
+// switch it to local boot. The lease is found by the caller address only, so
+// a host cannot change the boot state of another one.
+func bootDoneHandler(w http.ResponseWriter, r *http.Request) {
+	lease, found, err := findClientLease("", "", remoteIP(r))
+	if err != nil {
+		log.Error(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte("Internal error!"))
+
+		return
+	}
+
+	if !found {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("Unknown host!"))
+
+		return
+	}
+
+	if lease.Spec.Boot == v1alpha1.LeaseBootOnce {
+		finishBootOnce(lease)
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("OK"))
+}
+
+func finishBootOnce(lease v1alpha1.Lease) {
+	_, err := patchLeaseSpec(lease, func(spec *v1alpha1.LeaseSpec) {
+		spec.Boot = v1alpha1.LeaseBootLocalboot
+	})
+	if err != nil {
+		log.Error(err)
+
+		return
+	}
+
+	log.Infof("Lease %s finished boot once, switch to local boot", lease.Name)
+	leaseEvent(lease, corev1.EventTypeNormal, eventBootOnceFinished, "Host %s switched to local boot", lease.Spec.Mac)
+}
+
+func localBootScript(format string) string {
+	switch format {
+	case v1alpha1.PXEFormatGrub:
+		return "set timeout=0\nmenuentry local {\n  exit\n}\n"
+	case v1alpha1.PXEFormatPXELinux:
+		return "DEFAULT local\nLABEL local\n  LOCALBOOT 0\n"
+	case v1alpha1.PXEFormatCloudInit:
+		return "#cloud-config\n"
+	default:
+		return "#!ipxe\nsanboot --no-describe --drive 0x80 || exit\n"
+	}
+}
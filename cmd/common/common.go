@@ -1,19 +1,145 @@
 package common
 
 import (
+	"time"
+
+	"github.com/CRASH-Tech/dhcp-operator/cmd/kubernetes/generated/clientset/versioned"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 )
 
 type Config struct {
-	DhcpPort         int       `yaml:"dhcpPort"`
-	PxePort          int       `yaml:"pxePort"`
-	Log              LogConfig `yaml:"log"`
+	DhcpPort         int                  `yaml:"dhcpPort"`
+	PxePort          int                  `yaml:"pxePort"`
+	Log              LogConfig            `yaml:"log"`
+	Dns              DnsConfig            `yaml:"dns"`
+	Ha               HaConfig             `yaml:"ha"`
+	LeaderElection   LeaderElectionConfig `yaml:"leaderElection"`
+	Scope            string               `yaml:"scope"`
+	WatchNamespaces  []string             `yaml:"watchNamespaces"`
+	Webhook          WebhookConfig        `yaml:"webhook"`
+	Tftp             TftpConfig           `yaml:"tftp"`
+	Artifacts        ArtifactsConfig      `yaml:"artifacts"`
+	Http             HttpConfig           `yaml:"http"`
+	Ops              OpsConfig            `yaml:"ops"`
 	DynamicClient    *dynamic.DynamicClient
 	KubernetesClient *kubernetes.Clientset
+	DhcpClient       *versioned.Clientset
 }
 
 type LogConfig struct {
 	Level  string `yaml:"level"`
 	Format string `yaml:"format"`
 }
+
+type DnsConfig struct {
+	Mode      string `yaml:"mode"`
+	Namespace string `yaml:"namespace"`
+	TTL       int64  `yaml:"ttl"`
+}
+
+type HaConfig struct {
+	Mode string `yaml:"mode"`
+}
+
+type LeaderElectionConfig struct {
+	Enabled       bool          `yaml:"enabled"`
+	LeaseName     string        `yaml:"leaseName"`
+	LeaseDuration time.Duration `yaml:"leaseDuration"`
+	RenewDeadline time.Duration `yaml:"renewDeadline"`
+	RetryPeriod   time.Duration `yaml:"retryPeriod"`
+	LeaderLabel   bool          `yaml:"leaderLabel"`
+}
+
+type WebhookConfig struct {
+	Enabled  bool   `yaml:"enabled"`
+	Port     int    `yaml:"port"`
+	CertFile string `yaml:"certFile"`
+	KeyFile  string `yaml:"keyFile"`
+}
+
+type TftpConfig struct {
+	Enabled    bool   `yaml:"enabled"`
+	Port       int    `yaml:"port"`
+	NextServer string `yaml:"nextServer"`
+}
+
+type ArtifactsConfig struct {
+	Enabled         bool          `yaml:"enabled"`
+	CacheDir        string        `yaml:"cacheDir"`
+	VolumesDir      string        `yaml:"volumesDir"`
+	DownloadTimeout time.Duration `yaml:"downloadTimeout"`
+}
+
+type HttpConfig struct {
+	ReadHeaderTimeout time.Duration `yaml:"readHeaderTimeout"`
+	ReadTimeout       time.Duration `yaml:"readTimeout"`
+	WriteTimeout      time.Duration `yaml:"writeTimeout"`
+	IdleTimeout       time.Duration `yaml:"idleTimeout"`
+	RequireLease      bool          `yaml:"requireLease"`
+	Tls               TlsConfig     `yaml:"tls"`
+	Signing           SigningConfig `yaml:"signing"`
+}
+
+type TlsConfig struct {
+	Enabled         bool   `yaml:"enabled"`
+	Port            int    `yaml:"port"`
+	SecretName      string `yaml:"secretName"`
+	SecretNamespace string `yaml:"secretNamespace"`
+}
+
+type SigningConfig struct {
+	Enabled bool          `yaml:"enabled"`
+	KeyFile string        `yaml:"keyFile"`
+	TTL     time.Duration `yaml:"ttl"`
+}
+
+type OpsConfig struct {
+	Addr  string `yaml:"addr"`
+	Pprof bool   `yaml:"pprof"`
+}
+
+// NewConfig returns a config with defaults for the values which may be
+// omitted in the config file.
+func NewConfig() Config {
+	return Config{
+		LeaderElection: LeaderElectionConfig{
+			Enabled:       true,
+			LeaseName:     "dhcp-operator",
+			LeaseDuration: 15 * time.Second,
+			RenewDeadline: 10 * time.Second,
+			RetryPeriod:   2 * time.Second,
+			LeaderLabel:   true,
+		},
+		Webhook: WebhookConfig{
+			Port:     9443,
+			CertFile: "/etc/dhcp-operator/tls/tls.crt",
+			KeyFile:  "/etc/dhcp-operator/tls/tls.key",
+		},
+		Tftp: TftpConfig{
+			Port: 69,
+		},
+		Artifacts: ArtifactsConfig{
+			CacheDir:        "/var/cache/dhcp-operator/artifacts",
+			VolumesDir:      "/mnt/artifacts",
+			DownloadTimeout: 10 * time.Minute,
+		},
+		Http: HttpConfig{
+			ReadHeaderTimeout: 10 * time.Second,
+			ReadTimeout:       30 * time.Second,
+			WriteTimeout:      10 * time.Minute,
+			IdleTimeout:       2 * time.Minute,
+			Tls: TlsConfig{
+				Port:       8443,
+				SecretName: "dhcp-operator-pxe-tls",
+			},
+			Signing: SigningConfig{
+				KeyFile: "/etc/dhcp-operator/signing/key",
+				TTL:     time.Hour,
+			},
+		},
+		Ops: OpsConfig{
+			Addr: "0.0.0.0:8081",
+		},
+	}
+}
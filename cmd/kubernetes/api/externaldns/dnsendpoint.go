@@ -0,0 +1,22 @@
+package externaldns
+
+import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+type DNSEndpoint struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec DNSEndpointSpec `json:"spec"`
+}
+
+type DNSEndpointSpec struct {
+	Endpoints []Endpoint `json:"endpoints"`
+}
+
+type Endpoint struct {
+	DNSName    string            `json:"dnsName"`
+	Targets    []string          `json:"targets"`
+	RecordType string            `json:"recordType"`
+	RecordTTL  int64             `json:"recordTTL,omitempty"`
+	Labels     map[string]string `json:"labels,omitempty"`
+}
@@ -0,0 +1,60 @@
+package v1alpha1
+
+import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+// +genclient
+// +resourceName=bootartifact
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+type BootArtifact struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   BootArtifactSpec   `json:"spec"`
+	Status BootArtifactStatus `json:"status,omitempty"`
+}
+
+// BootArtifactSpec describes a file served under /static/<path>. Exactly one
+// of URL, ConfigMap and PersistentVolumeClaim is set.
+type BootArtifactSpec struct {
+	Path                  string                             `json:"path"`
+	URL                   string                             `json:"url,omitempty"`
+	Checksum              string                             `json:"checksum,omitempty"`
+	ConfigMap             *BootArtifactConfigMap             `json:"configMap,omitempty"`
+	PersistentVolumeClaim *BootArtifactPersistentVolumeClaim `json:"persistentVolumeClaim,omitempty"`
+}
+
+type BootArtifactConfigMap struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+	Key       string `json:"key"`
+}
+
+type BootArtifactPersistentVolumeClaim struct {
+	ClaimName string `json:"claimName"`
+	Path      string `json:"path"`
+}
+
+type BootArtifactStatus struct {
+	ObservedGeneration int64        `json:"observedGeneration,omitempty"`
+	Phase              string       `json:"phase,omitempty"`
+	Checksum           string       `json:"checksum,omitempty"`
+	Size               int64        `json:"size,omitempty"`
+	Message            string       `json:"message,omitempty"`
+	LastSync           *metav1.Time `json:"lastSync,omitempty"`
+}
+
+const (
+	BootArtifactPending = "Pending"
+	BootArtifactReady   = "Ready"
+	BootArtifactFailed  = "Failed"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+type BootArtifactList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []BootArtifact `json:"items"`
+}
@@ -0,0 +1,10 @@
+/*
+This is free and unencumbered software released into the public domain.
+See the LICENSE file in the repository root for details.
+*/
+
+// +k8s:deepcopy-gen=package
+// +groupName=dhcp.xfix.org
+
+// Package v1alpha1 contains the dhcp.xfix.org API types.
+package v1alpha1
@@ -1,24 +1,59 @@
 package v1alpha1
 
-import "github.com/CRASH-Tech/dhcp-operator/cmd/kubernetes/api"
+import (
+	"strconv"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +resourceName=lease
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
 type Lease struct {
-	APIVersion string                     `json:"apiVersion"`
-	Kind       string                     `json:"kind"`
-	Metadata   api.CustomResourceMetadata `json:"metadata"`
-	Spec       LeaseSpec                  `json:"spec"`
-	Status     LeaseStatus                `json:"status"`
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   LeaseSpec   `json:"spec"`
+	Status LeaseStatus `json:"status,omitempty"`
 }
 
 type LeaseSpec struct {
-	Ip     string `json:"ip"`
-	Mac    string `json:"mac"`
-	Static bool   `json:"static"`
-	Pool   string `json:"pool"`
+	Ip       string `json:"ip"`
+	Mac      string `json:"mac"`
+	Static   bool   `json:"static"`
+	Pool     string `json:"pool"`
+	Hostname string `json:"hostname,omitempty"`
+	Uuid     string `json:"uuid,omitempty"`
+	PXE      string `json:"pxe,omitempty"`
+	Boot     string `json:"boot,omitempty"`
 }
 
+const (
+	LeaseBootNetboot   = "netboot"
+	LeaseBootLocalboot = "localboot"
+	LeaseBootOnce      = "boot-once"
+)
+
 type LeaseStatus struct {
 	Hostname string `json:"hostname"`
 	Starts   string `json:"starts"`
 	Ends     string `json:"ends"`
+	Expires  string `json:"expires,omitempty"`
+}
+
+// SetEnds sets the lease end as unix time and as RFC 3339 for printing.
+func (status *LeaseStatus) SetEnds(t time.Time) {
+	status.Ends = strconv.FormatInt(t.Unix(), 10)
+	status.Expires = t.UTC().Format(time.RFC3339)
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+type LeaseList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []Lease `json:"items"`
 }
@@ -3,15 +3,29 @@ package v1alpha1
 import (
 	"net"
 
-	"github.com/CRASH-Tech/dhcp-operator/cmd/kubernetes/api"
 	log "github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// +genclient
+// +resourceName=pool
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
 type Pool struct {
-	APIVersion string                     `json:"apiVersion"`
-	Kind       string                     `json:"kind"`
-	Metadata   api.CustomResourceMetadata `json:"metadata"`
-	Spec       PoolSpec                   `json:"spec"`
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   PoolSpec   `json:"spec"`
+	Status PoolStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+type PoolList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []Pool `json:"items"`
 }
 
 type PoolSpec struct {
@@ -26,9 +40,37 @@ type PoolSpec struct {
 	Domain    string   `json:"domain"`
 	Lease     string   `json:"lease"`
 	Filename  string   `json:"filename"`
-	Static    bool     `json:"static"`
+	// HttpFilename is the bootfile URL for UEFI HTTP Boot clients.
+	HttpFilename string       `json:"httpFilename,omitempty"`
+	PXE          string       `json:"pxe,omitempty"`
+	Static       bool         `json:"static"`
+	Hostname     PoolHostname `json:"hostname"`
 }
 
+type PoolHostname struct {
+	Sanitize bool   `json:"sanitize"`
+	Template string `json:"template"`
+	Conflict string `json:"conflict"`
+}
+
+type PoolStatus struct {
+	ObservedGeneration int64              `json:"observedGeneration,omitempty"`
+	Total              int                `json:"total"`
+	Used               int                `json:"used"`
+	Free               int                `json:"free"`
+	Reserved           int                `json:"reserved"`
+	Quarantined        int                `json:"quarantined"`
+	LastAllocation     *metav1.Time       `json:"lastAllocation,omitempty"`
+	OutOfRange         []string           `json:"outOfRange,omitempty"`
+	Conditions         []metav1.Condition `json:"conditions,omitempty"`
+}
+
+const (
+	PoolConditionValid       = "Valid"
+	PoolConditionExhausted   = "Exhausted"
+	PoolConditionOverlapping = "Overlapping"
+)
+
 func (pool *Pool) GetDNS() []net.IP {
 	var result []net.IP
 
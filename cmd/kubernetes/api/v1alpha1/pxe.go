@@ -1,14 +1,38 @@
 package v1alpha1
 
-import "github.com/CRASH-Tech/dhcp-operator/cmd/kubernetes/api"
+import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+// +genclient
+// +resourceName=pxe
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
 type PXE struct {
-	APIVersion string                     `json:"apiVersion"`
-	Kind       string                     `json:"kind"`
-	Metadata   api.CustomResourceMetadata `json:"metadata"`
-	Spec       PXESpec                    `json:"spec"`
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec PXESpec `json:"spec"`
 }
 
 type PXESpec struct {
-	Data string `json:"data"`
+	Data     string                `json:"data"`
+	Format   string                `json:"format,omitempty"`
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+	Callback bool                  `json:"callback,omitempty"`
+}
+
+const (
+	PXEFormatIPXE      = "ipxe"
+	PXEFormatGrub      = "grub"
+	PXEFormatPXELinux  = "pxelinux"
+	PXEFormatCloudInit = "cloud-init"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+type PXEList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []PXE `json:"items"`
 }
@@ -0,0 +1,103 @@
+package kubernetes
+
+import (
+	"github.com/CRASH-Tech/dhcp-operator/cmd/kubernetes/api/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/util/retry"
+)
+
+type BootArtifact struct {
+	client *Client
+}
+
+func (BootArtifact *BootArtifact) Get(namespace, name string) (v1alpha1.BootArtifact, error) {
+	var result *v1alpha1.BootArtifact
+	err := withRetry(func() (err error) {
+		result, err = BootArtifact.client.dhcp.DhcpV1alpha1().BootArtifacts(namespace).Get(BootArtifact.client.ctx, name, metav1.GetOptions{})
+
+		return err
+	})
+	if err != nil {
+		return v1alpha1.BootArtifact{}, err
+	}
+
+	return *result, nil
+}
+
+func (BootArtifact *BootArtifact) GetAll() ([]v1alpha1.BootArtifact, error) {
+	var result []v1alpha1.BootArtifact
+	for _, namespace := range BootArtifact.client.namespaces {
+		var items *v1alpha1.BootArtifactList
+		err := withRetry(func() (err error) {
+			items, err = BootArtifact.client.dhcp.DhcpV1alpha1().BootArtifacts(namespace).List(BootArtifact.client.ctx, metav1.ListOptions{})
+
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, items.Items...)
+	}
+
+	return result, nil
+}
+
+// UpdateStatus replaces the artifact status. The status is owned by the
+// operator, so on conflict it is written over a fresh copy of the artifact.
+func (BootArtifact *BootArtifact) UpdateStatus(m v1alpha1.BootArtifact) (v1alpha1.BootArtifact, error) {
+	artifacts := BootArtifact.client.dhcp.DhcpV1alpha1().BootArtifacts(m.Namespace)
+	status := m.Status
+
+	var result *v1alpha1.BootArtifact
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() (err error) {
+		m.Status = status
+
+		result, err = artifacts.UpdateStatus(BootArtifact.client.ctx, &m, metav1.UpdateOptions{})
+		if apierrors.IsConflict(err) {
+			fresh, getErr := artifacts.Get(BootArtifact.client.ctx, m.Name, metav1.GetOptions{})
+			if getErr != nil {
+				return getErr
+			}
+
+			m = *fresh
+		}
+
+		return err
+	})
+	if err != nil {
+		return v1alpha1.BootArtifact{}, wrapError(err)
+	}
+
+	return *result, nil
+}
+
+// GetConfigMapData returns the value of key in the ConfigMap, binary data
+// included.
+func (BootArtifact *BootArtifact) GetConfigMapData(namespace, name, key string) ([]byte, error) {
+	var data []byte
+	err := withRetry(func() error {
+		configMap, err := BootArtifact.client.kubernetes.CoreV1().ConfigMaps(namespace).Get(BootArtifact.client.ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		if value, ok := configMap.BinaryData[key]; ok {
+			data = value
+
+			return nil
+		}
+
+		value, ok := configMap.Data[key]
+		if !ok {
+			return apierrors.NewNotFound(schema.GroupResource{Resource: "configmaps"}, name+"/"+key)
+		}
+		data = []byte(value)
+
+		return nil
+	})
+
+	return data, err
+}
@@ -0,0 +1,128 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/CRASH-Tech/dhcp-operator/cmd/kubernetes/api/v1alpha1"
+	"github.com/CRASH-Tech/dhcp-operator/cmd/kubernetes/generated/informers/externalversions"
+	listers "github.com/CRASH-Tech/dhcp-operator/cmd/kubernetes/generated/listers/dhcp/v1alpha1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+type Cache struct {
+	leases []listers.LeaseLister
+	pools  []cache.SharedIndexInformer
+	synced []cache.InformerSynced
+}
+
+// StartCache starts watching Leases and Pools. It runs independently of the
+// leader election, so a standby replica has a warm cache when it takes over.
+func (client *Client) StartCache(ctx context.Context, resync time.Duration) {
+	client.cache = &Cache{}
+
+	for _, namespace := range client.namespaces {
+		factory := externalversions.NewSharedInformerFactoryWithOptions(client.dhcp, resync, externalversions.WithNamespace(namespace))
+		leases := factory.Dhcp().V1alpha1().Leases()
+		pools := factory.Dhcp().V1alpha1().Pools()
+
+		client.cache.leases = append(client.cache.leases, leases.Lister())
+		client.cache.pools = append(client.cache.pools, pools.Informer())
+		client.cache.synced = append(client.cache.synced, leases.Informer().HasSynced, pools.Informer().HasSynced)
+
+		factory.Start(ctx.Done())
+	}
+}
+
+func (client *Client) WaitForCacheSync(ctx context.Context) bool {
+	if client.cache == nil {
+		return false
+	}
+
+	return cache.WaitForCacheSync(ctx.Done(), client.cache.synced...)
+}
+
+// CacheSynced reports whether the informers completed their initial list.
+func (client *Client) CacheSynced() bool {
+	return client.isCacheSynced()
+}
+
+func (client *Client) isCacheSynced() bool {
+	if client.cache == nil {
+		return false
+	}
+
+	for _, synced := range client.cache.synced {
+		if !synced() {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (Lease *Lease) GetAllCached() ([]v1alpha1.Lease, error) {
+	if !Lease.client.isCacheSynced() {
+		return Lease.GetAll()
+	}
+
+	var result []v1alpha1.Lease
+	for _, lister := range Lease.client.cache.leases {
+		items, err := lister.List(labels.Everything())
+		if err != nil {
+			return nil, err
+		}
+
+		for _, item := range items {
+			result = append(result, *item.DeepCopy())
+		}
+	}
+
+	return result, nil
+}
+
+func (Pool *Pool) GetAllCached() ([]v1alpha1.Pool, error) {
+	if !Pool.client.isCacheSynced() {
+		return Pool.GetAll()
+	}
+
+	var result []v1alpha1.Pool
+	for _, informer := range Pool.client.cache.pools {
+		items, err := listers.NewPoolLister(informer.GetIndexer()).List(labels.Everything())
+		if err != nil {
+			return nil, err
+		}
+
+		for _, item := range items {
+			result = append(result, *item.DeepCopy())
+		}
+	}
+
+	return result, nil
+}
+
+func (Pool *Pool) GetCached(namespace, name string) (v1alpha1.Pool, error) {
+	if !Pool.client.isCacheSynced() {
+		return Pool.Get(namespace, name)
+	}
+
+	key := name
+	if namespace != "" {
+		key = namespace + "/" + name
+	}
+
+	for _, informer := range Pool.client.cache.pools {
+		item, exists, err := informer.GetIndexer().GetByKey(key)
+		if err != nil {
+			return v1alpha1.Pool{}, err
+		}
+
+		if exists {
+			return *item.(*v1alpha1.Pool).DeepCopy(), nil
+		}
+	}
+
+	return v1alpha1.Pool{}, fmt.Errorf("%w: pool %s", ErrNotFound, key)
+}
@@ -3,11 +3,9 @@ package kubernetes
 import (
 	"context"
 
+	"github.com/CRASH-Tech/dhcp-operator/cmd/kubernetes/generated/clientset/versioned"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
-	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
-	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 )
@@ -16,124 +14,37 @@ type Client struct {
 	ctx        context.Context
 	dynamic    dynamic.DynamicClient
 	kubernetes kubernetes.Clientset
+	dhcp       versioned.Interface
+	namespaces []string
+	cache      *Cache
 }
 
 type V1alpha1 struct {
 	client *Client
 }
 
-func NewClient(ctx context.Context, dynamic dynamic.DynamicClient, clientSet kubernetes.Clientset) *Client {
+type ExternalDNS struct {
+	client *Client
+}
+
+// NewClient returns a client working with dhcp.xfix.org resources in the
+// given namespaces. An empty list means cluster scoped resources.
+func NewClient(ctx context.Context, dynamic dynamic.DynamicClient, clientSet kubernetes.Clientset, dhcp versioned.Interface, namespaces []string) *Client {
+	if len(namespaces) == 0 {
+		namespaces = []string{metav1.NamespaceAll}
+	}
+
 	client := Client{
 		ctx:        ctx,
 		dynamic:    dynamic,
 		kubernetes: clientSet,
+		dhcp:       dhcp,
+		namespaces: namespaces,
 	}
 
 	return &client
 }
 
-func (client *Client) dynamicCreate(resourceId schema.GroupVersionResource, obj interface{}) ([]byte, error) {
-	uns, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&obj)
-	if err != nil {
-		return nil, err
-	}
-
-	uObj := unstructured.Unstructured{}
-
-	uObj.Object = uns
-
-	item, err := client.dynamic.Resource(resourceId).Create(client.ctx, &uObj, metav1.CreateOptions{})
-	if err != nil {
-		return nil, err
-	}
-
-	jsonData, err := item.MarshalJSON()
-	if err != nil {
-		return nil, err
-	}
-
-	return jsonData, nil
-}
-
-func (client *Client) dynamicGet(resourceId schema.GroupVersionResource, name string) ([]byte, error) {
-
-	item, err := client.dynamic.Resource(resourceId).Get(client.ctx, name, metav1.GetOptions{})
-	if err != nil {
-		return nil, err
-	}
-
-	jsonData, err := item.MarshalJSON()
-	if err != nil {
-		return nil, err
-	}
-
-	return jsonData, nil
-}
-
-func (client *Client) dynamicGetAll(resourceId schema.GroupVersionResource) ([][]byte, error) {
-
-	items, err := client.dynamic.Resource(resourceId).List(client.ctx, metav1.ListOptions{})
-	if err != nil {
-		return nil, err
-	}
-
-	var result [][]byte
-	for _, item := range items.Items {
-		jsonData, err := item.MarshalJSON()
-		if err != nil {
-			return nil, err
-		}
-		result = append(result, jsonData)
-	}
-
-	return result, nil
-}
-
-func (client *Client) dynamicPatch(resourceId schema.GroupVersionResource, name string, patch []byte) ([]byte, error) {
-
-	item, err := client.dynamic.Resource(resourceId).Patch(client.ctx, name, types.MergePatchType, patch, metav1.PatchOptions{})
-	if err != nil {
-		return nil, err
-	}
-
-	jsonData, err := item.MarshalJSON()
-	if err != nil {
-		return nil, err
-	}
-
-	return jsonData, nil
-}
-
-func (client *Client) dynamicDelete(resourceId schema.GroupVersionResource, name string) error {
-
-	err := client.dynamic.Resource(resourceId).Delete(client.ctx, name, metav1.DeleteOptions{})
-	if err != nil {
-		return err
-	}
-
-	return nil
-}
-
-func (client *Client) dynamicUpdateStatus(resourceId schema.GroupVersionResource, name string, patch []byte) ([]byte, error) {
-	var data unstructured.Unstructured
-	err := data.UnmarshalJSON(patch)
-	if err != nil {
-		return nil, err
-	}
-
-	result, err := client.dynamic.Resource(resourceId).UpdateStatus(client.ctx, &data, metav1.UpdateOptions{})
-	if err != nil {
-		return nil, err
-	}
-
-	jsonData, err := result.MarshalJSON()
-	if err != nil {
-		return nil, err
-	}
-
-	return jsonData, nil
-}
-
 func (client *Client) V1alpha1() *V1alpha1 {
 	result := V1alpha1{
 		client: client,
@@ -145,11 +56,6 @@ func (client *Client) V1alpha1() *V1alpha1 {
 func (v1alpha1 *V1alpha1) Pool() *Pool {
 	pool := Pool{
 		client: v1alpha1.client,
-		resourceId: schema.GroupVersionResource{
-			Group:    "dhcp.xfix.org",
-			Version:  "v1alpha1",
-			Resource: "pool",
-		},
 	}
 
 	return &pool
@@ -158,11 +64,6 @@ func (v1alpha1 *V1alpha1) Pool() *Pool {
 func (v1alpha1 *V1alpha1) Lease() *Lease {
 	lease := Lease{
 		client: v1alpha1.client,
-		resourceId: schema.GroupVersionResource{
-			Group:    "dhcp.xfix.org",
-			Version:  "v1alpha1",
-			Resource: "lease",
-		},
 	}
 
 	return &lease
@@ -171,12 +72,37 @@ func (v1alpha1 *V1alpha1) Lease() *Lease {
 func (v1alpha1 *V1alpha1) PXE() *PXE {
 	pxe := PXE{
 		client: v1alpha1.client,
+	}
+
+	return &pxe
+}
+
+func (v1alpha1 *V1alpha1) BootArtifact() *BootArtifact {
+	bootArtifact := BootArtifact{
+		client: v1alpha1.client,
+	}
+
+	return &bootArtifact
+}
+
+func (client *Client) ExternalDNS() *ExternalDNS {
+	result := ExternalDNS{
+		client: client,
+	}
+
+	return &result
+}
+
+func (externalDNS *ExternalDNS) DNSEndpoint(namespace string) *DNSEndpoint {
+	dnsEndpoint := DNSEndpoint{
+		client: externalDNS.client,
 		resourceId: schema.GroupVersionResource{
-			Group:    "dhcp.xfix.org",
+			Group:    "externaldns.k8s.io",
 			Version:  "v1alpha1",
-			Resource: "pxe",
+			Resource: "dnsendpoints",
 		},
+		namespace: namespace,
 	}
 
-	return &pxe
+	return &dnsEndpoint
 }
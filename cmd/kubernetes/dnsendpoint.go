@@ -0,0 +1,92 @@
+package kubernetes
+
+import (
+	"encoding/json"
+
+	"github.com/CRASH-Tech/dhcp-operator/cmd/kubernetes/api/externaldns"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+type DNSEndpoint struct {
+	client     *Client
+	resourceId schema.GroupVersionResource
+	namespace  string
+}
+
+func (DNSEndpoint *DNSEndpoint) Create(e externaldns.DNSEndpoint) (externaldns.DNSEndpoint, error) {
+	e.APIVersion = "externaldns.k8s.io/v1alpha1"
+	e.Kind = "DNSEndpoint"
+	e.Namespace = DNSEndpoint.namespace
+
+	uns, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&e)
+	if err != nil {
+		return externaldns.DNSEndpoint{}, err
+	}
+
+	item, err := DNSEndpoint.client.dynamic.Resource(DNSEndpoint.resourceId).Namespace(DNSEndpoint.namespace).Create(DNSEndpoint.client.ctx, &unstructured.Unstructured{Object: uns}, metav1.CreateOptions{})
+	if err != nil {
+		return externaldns.DNSEndpoint{}, err
+	}
+
+	return DNSEndpoint.decode(item)
+}
+
+func (DNSEndpoint *DNSEndpoint) GetAll(selector string) ([]externaldns.DNSEndpoint, error) {
+	items, err := DNSEndpoint.client.dynamic.Resource(DNSEndpoint.resourceId).Namespace(DNSEndpoint.namespace).List(DNSEndpoint.client.ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, err
+	}
+
+	var result []externaldns.DNSEndpoint
+	for _, item := range items.Items {
+		q, err := DNSEndpoint.decode(&item)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, q)
+	}
+
+	return result, nil
+}
+
+func (DNSEndpoint *DNSEndpoint) PatchSpec(e externaldns.DNSEndpoint) (externaldns.DNSEndpoint, error) {
+	patch := map[string]interface{}{
+		"spec": e.Spec,
+	}
+
+	jsonData, err := json.Marshal(patch)
+	if err != nil {
+		return externaldns.DNSEndpoint{}, err
+	}
+
+	item, err := DNSEndpoint.client.dynamic.Resource(DNSEndpoint.resourceId).Namespace(DNSEndpoint.namespace).Patch(DNSEndpoint.client.ctx, e.Name, types.MergePatchType, jsonData, metav1.PatchOptions{})
+	if err != nil {
+		return externaldns.DNSEndpoint{}, err
+	}
+
+	return DNSEndpoint.decode(item)
+}
+
+func (DNSEndpoint *DNSEndpoint) Delete(e externaldns.DNSEndpoint) error {
+	return DNSEndpoint.client.dynamic.Resource(DNSEndpoint.resourceId).Namespace(DNSEndpoint.namespace).Delete(DNSEndpoint.client.ctx, e.Name, metav1.DeleteOptions{})
+}
+
+func (DNSEndpoint *DNSEndpoint) decode(item *unstructured.Unstructured) (externaldns.DNSEndpoint, error) {
+	jsonData, err := item.MarshalJSON()
+	if err != nil {
+		return externaldns.DNSEndpoint{}, err
+	}
+
+	var result externaldns.DNSEndpoint
+	err = json.Unmarshal(jsonData, &result)
+	if err != nil {
+		return externaldns.DNSEndpoint{}, err
+	}
+
+	return result, nil
+}
@@ -8,19 +8,17 @@ import (
 	"time"
 
 	"github.com/CRASH-Tech/dhcp-operator/cmd/kubernetes/api/v1alpha1"
-	"k8s.io/apimachinery/pkg/runtime/schema"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
 )
 
 type Lease struct {
-	client     *Client
-	resourceId schema.GroupVersionResource
+	client *Client
 }
 
 func (Lease *Lease) Create(l v1alpha1.Lease) (v1alpha1.Lease, error) {
-	l.APIVersion = "dhcp.xfix.org/v1alpha1"
-	l.Kind = "Lease"
-	l.Metadata.CreationTimestamp = time.Now().Format("2006-01-02T15:04:05Z")
-
 	ip := net.ParseIP(l.Spec.Ip)
 	if ip == nil {
 		return v1alpha1.Lease{}, errors.New("cannot create lease, nil ip")
@@ -39,127 +37,166 @@ func (Lease *Lease) Create(l v1alpha1.Lease) (v1alpha1.Lease, error) {
 		return v1alpha1.Lease{}, errors.New("cannot create lease, empty data")
 	}
 
-	item, err := Lease.client.dynamicCreate(Lease.resourceId, &l)
+	result, err := Lease.client.dhcp.DhcpV1alpha1().Leases(l.Namespace).Create(Lease.client.ctx, &l, metav1.CreateOptions{})
 	if err != nil {
-		return v1alpha1.Lease{}, err
+		return v1alpha1.Lease{}, wrapError(err)
 	}
 
-	var result v1alpha1.Lease
-	err = json.Unmarshal(item, &result)
-	if err != nil {
-		return v1alpha1.Lease{}, err
-	}
-
-	return result, nil
+	return *result, nil
 }
 
-func (Lease *Lease) Get(name string) (v1alpha1.Lease, error) {
-	item, err := Lease.client.dynamicGet(Lease.resourceId, name)
-	if err != nil {
-		return v1alpha1.Lease{}, err
-	}
+func (Lease *Lease) Get(namespace, name string) (v1alpha1.Lease, error) {
+	var result *v1alpha1.Lease
+	err := withRetry(func() (err error) {
+		result, err = Lease.client.dhcp.DhcpV1alpha1().Leases(namespace).Get(Lease.client.ctx, name, metav1.GetOptions{})
 
-	var result v1alpha1.Lease
-	err = json.Unmarshal(item, &result)
+		return err
+	})
 	if err != nil {
 		return v1alpha1.Lease{}, err
 	}
 
-	return result, nil
+	return *result, nil
 }
 
 func (Lease *Lease) GetAll() ([]v1alpha1.Lease, error) {
-	items, err := Lease.client.dynamicGetAll(Lease.resourceId)
-	if err != nil {
-		panic(err)
-	}
-
 	var result []v1alpha1.Lease
-	for _, item := range items {
-		var q v1alpha1.Lease
-		err = json.Unmarshal(item, &q)
+	for _, namespace := range Lease.client.namespaces {
+		var items *v1alpha1.LeaseList
+		err := withRetry(func() (err error) {
+			items, err = Lease.client.dhcp.DhcpV1alpha1().Leases(namespace).List(Lease.client.ctx, metav1.ListOptions{})
+
+			return err
+		})
 		if err != nil {
 			return nil, err
 		}
 
-		result = append(result, q)
+		result = append(result, items.Items...)
 	}
 
 	return result, nil
 }
 
+// Patch updates the lease spec. The resourceVersion of m is sent as a
+// precondition, so a concurrent change results in a conflict error.
 func (Lease *Lease) Patch(m v1alpha1.Lease) (v1alpha1.Lease, error) {
-	jsonData, err := json.Marshal(m)
-	if err != nil {
-		return v1alpha1.Lease{}, err
+	patch := map[string]interface{}{
+		"spec": m.Spec,
+	}
+	if m.ResourceVersion != "" {
+		patch["metadata"] = map[string]interface{}{
+			"resourceVersion": m.ResourceVersion,
+		}
 	}
 
-	resp, err := Lease.client.dynamicPatch(Lease.resourceId, m.Metadata.Name, jsonData)
+	jsonData, err := json.Marshal(patch)
 	if err != nil {
 		return v1alpha1.Lease{}, err
 	}
 
-	var result v1alpha1.Lease
-	err = json.Unmarshal(resp, &result)
+	result, err := Lease.client.dhcp.DhcpV1alpha1().Leases(m.Namespace).Patch(Lease.client.ctx, m.Name, types.MergePatchType, jsonData, metav1.PatchOptions{})
 	if err != nil {
-		return v1alpha1.Lease{}, err
+		return v1alpha1.Lease{}, wrapError(err)
 	}
 
-	return result, nil
+	return *result, nil
 }
 
 func (Lease *Lease) Delete(m v1alpha1.Lease) error {
-	err := Lease.client.dynamicDelete(Lease.resourceId, m.Metadata.Name)
+	err := Lease.client.dhcp.DhcpV1alpha1().Leases(m.Namespace).Delete(Lease.client.ctx, m.Name, metav1.DeleteOptions{})
 	if err != nil {
-		return err
+		return wrapError(err)
 	}
 
 	return nil
 }
 
-func (Lease *Lease) UpdateStatus(m v1alpha1.Lease) (v1alpha1.Lease, error) {
-	jsonData, err := json.Marshal(m)
-	if err != nil {
-		return v1alpha1.Lease{}, err
-	}
-
-	resp, err := Lease.client.dynamicUpdateStatus(Lease.resourceId, m.Metadata.Name, jsonData)
+// SetAnnotations merges annotations into the lease, a nil value removes the
+// annotation.
+func (Lease *Lease) SetAnnotations(m v1alpha1.Lease, annotations map[string]*string) (v1alpha1.Lease, error) {
+	jsonData, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": annotations,
+		},
+	})
 	if err != nil {
 		return v1alpha1.Lease{}, err
 	}
 
-	var result v1alpha1.Lease
-	err = json.Unmarshal(resp, &result)
+	result, err := Lease.client.dhcp.DhcpV1alpha1().Leases(m.Namespace).Patch(Lease.client.ctx, m.Name, types.MergePatchType, jsonData, metav1.PatchOptions{})
 	if err != nil {
-		return v1alpha1.Lease{}, err
+		return v1alpha1.Lease{}, wrapError(err)
 	}
 
-	return result, nil
+	return *result, nil
 }
 
-func (Lease *Lease) Renew(m v1alpha1.Lease, hostname string, duration time.Duration) (v1alpha1.Lease, error) {
-	m.Status.Hostname = hostname
-	m.Status.Ends = strconv.FormatInt(time.Now().Add(duration).Unix(), 10)
-
-	if m.Status.Starts == "" {
-		m.Status.Starts = strconv.FormatInt(time.Now().Unix(), 10)
+// SetFinalizers replaces the finalizers of the lease. The resourceVersion of m
+// is sent as a precondition, so a concurrent change results in a conflict error.
+func (Lease *Lease) SetFinalizers(m v1alpha1.Lease, finalizers []string) (v1alpha1.Lease, error) {
+	metadata := map[string]interface{}{
+		"finalizers": finalizers,
+	}
+	if m.ResourceVersion != "" {
+		metadata["resourceVersion"] = m.ResourceVersion
 	}
 
-	jsonData, err := json.Marshal(m)
+	jsonData, err := json.Marshal(map[string]interface{}{"metadata": metadata})
 	if err != nil {
 		return v1alpha1.Lease{}, err
 	}
 
-	resp, err := Lease.client.dynamicUpdateStatus(Lease.resourceId, m.Metadata.Name, jsonData)
+	result, err := Lease.client.dhcp.DhcpV1alpha1().Leases(m.Namespace).Patch(Lease.client.ctx, m.Name, types.MergePatchType, jsonData, metav1.PatchOptions{})
 	if err != nil {
-		return v1alpha1.Lease{}, err
+		return v1alpha1.Lease{}, wrapError(err)
 	}
 
-	var result v1alpha1.Lease
-	err = json.Unmarshal(resp, &result)
+	return *result, nil
+}
+
+// UpdateStatus applies mutate to the lease status. On conflict mutate runs
+// again on the fresh lease, so it must only set the fields it computes.
+func (Lease *Lease) UpdateStatus(m v1alpha1.Lease, mutate func(*v1alpha1.Lease)) (v1alpha1.Lease, error) {
+	return Lease.updateStatus(m, mutate)
+}
+
+func (Lease *Lease) Renew(m v1alpha1.Lease, hostname string, duration time.Duration) (v1alpha1.Lease, error) {
+	return Lease.updateStatus(m, func(l *v1alpha1.Lease) {
+		l.Status.Hostname = hostname
+		l.Status.SetEnds(time.Now().Add(duration))
+
+		if l.Status.Starts == "" {
+			l.Status.Starts = strconv.FormatInt(time.Now().Unix(), 10)
+		}
+	})
+}
+
+// updateStatus applies mutate and updates the status with the resourceVersion
+// as a precondition. On conflict the lease is re-read and mutate is applied
+// again to the fresh object.
+func (Lease *Lease) updateStatus(m v1alpha1.Lease, mutate func(*v1alpha1.Lease)) (v1alpha1.Lease, error) {
+	leases := Lease.client.dhcp.DhcpV1alpha1().Leases(m.Namespace)
+
+	var result *v1alpha1.Lease
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() (err error) {
+		mutate(&m)
+
+		result, err = leases.UpdateStatus(Lease.client.ctx, &m, metav1.UpdateOptions{})
+		if apierrors.IsConflict(err) {
+			fresh, getErr := leases.Get(Lease.client.ctx, m.Name, metav1.GetOptions{})
+			if getErr != nil {
+				return getErr
+			}
+
+			m = *fresh
+		}
+
+		return err
+	})
 	if err != nil {
-		return v1alpha1.Lease{}, err
+		return v1alpha1.Lease{}, wrapError(err)
 	}
 
-	return result, nil
+	return *result, nil
 }
@@ -2,66 +2,55 @@ package kubernetes
 
 import (
 	"encoding/json"
-	"time"
 
 	"github.com/CRASH-Tech/dhcp-operator/cmd/kubernetes/api/v1alpha1"
-	"k8s.io/apimachinery/pkg/runtime/schema"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
 )
 
 type Pool struct {
-	client     *Client
-	resourceId schema.GroupVersionResource
+	client *Client
 }
 
 func (Pool *Pool) Create(p v1alpha1.Pool) (v1alpha1.Pool, error) {
-	p.APIVersion = "dhcp.xfix.org/v1alpha1"
-	p.Kind = "Pool"
-	p.Metadata.CreationTimestamp = time.Now().Format("2006-01-02T15:04:05Z")
-
-	item, err := Pool.client.dynamicCreate(Pool.resourceId, &p)
-	if err != nil {
-		return v1alpha1.Pool{}, err
-	}
-
-	var result v1alpha1.Pool
-	err = json.Unmarshal(item, &result)
+	result, err := Pool.client.dhcp.DhcpV1alpha1().Pools(p.Namespace).Create(Pool.client.ctx, &p, metav1.CreateOptions{})
 	if err != nil {
-		return v1alpha1.Pool{}, err
+		return v1alpha1.Pool{}, wrapError(err)
 	}
 
-	return result, nil
+	return *result, nil
 }
 
-func (Pool *Pool) Get(name string) (v1alpha1.Pool, error) {
-	item, err := Pool.client.dynamicGet(Pool.resourceId, name)
-	if err != nil {
-		return v1alpha1.Pool{}, err
-	}
+func (Pool *Pool) Get(namespace, name string) (v1alpha1.Pool, error) {
+	var result *v1alpha1.Pool
+	err := withRetry(func() (err error) {
+		result, err = Pool.client.dhcp.DhcpV1alpha1().Pools(namespace).Get(Pool.client.ctx, name, metav1.GetOptions{})
 
-	var result v1alpha1.Pool
-	err = json.Unmarshal(item, &result)
+		return err
+	})
 	if err != nil {
 		return v1alpha1.Pool{}, err
 	}
 
-	return result, nil
+	return *result, nil
 }
 
 func (Pool *Pool) GetAll() ([]v1alpha1.Pool, error) {
-	items, err := Pool.client.dynamicGetAll(Pool.resourceId)
-	if err != nil {
-		panic(err)
-	}
-
 	var result []v1alpha1.Pool
-	for _, item := range items {
-		var q v1alpha1.Pool
-		err = json.Unmarshal(item, &q)
+	for _, namespace := range Pool.client.namespaces {
+		var items *v1alpha1.PoolList
+		err := withRetry(func() (err error) {
+			items, err = Pool.client.dhcp.DhcpV1alpha1().Pools(namespace).List(Pool.client.ctx, metav1.ListOptions{})
+
+			return err
+		})
 		if err != nil {
 			return nil, err
 		}
 
-		result = append(result, q)
+		result = append(result, items.Items...)
 	}
 
 	return result, nil
@@ -73,36 +62,62 @@ func (Pool *Pool) Patch(m v1alpha1.Pool) (v1alpha1.Pool, error) {
 		return v1alpha1.Pool{}, err
 	}
 
-	resp, err := Pool.client.dynamicPatch(Pool.resourceId, m.Metadata.Name, jsonData)
+	result, err := Pool.client.dhcp.DhcpV1alpha1().Pools(m.Namespace).Patch(Pool.client.ctx, m.Name, types.MergePatchType, jsonData, metav1.PatchOptions{})
 	if err != nil {
-		return v1alpha1.Pool{}, err
+		return v1alpha1.Pool{}, wrapError(err)
 	}
 
-	var result v1alpha1.Pool
-	err = json.Unmarshal(resp, &result)
-	if err != nil {
-		return v1alpha1.Pool{}, err
-	}
-
-	return result, nil
+	return *result, nil
 }
 
-func (Pool *Pool) UpdateStatus(m v1alpha1.Pool) (v1alpha1.Pool, error) {
-	jsonData, err := json.Marshal(m)
+// SetFinalizers replaces the finalizers of the pool. The resourceVersion of m
+// is sent as a precondition, so a concurrent change results in a conflict error.
+func (Pool *Pool) SetFinalizers(m v1alpha1.Pool, finalizers []string) (v1alpha1.Pool, error) {
+	metadata := map[string]interface{}{
+		"finalizers": finalizers,
+	}
+	if m.ResourceVersion != "" {
+		metadata["resourceVersion"] = m.ResourceVersion
+	}
+
+	jsonData, err := json.Marshal(map[string]interface{}{"metadata": metadata})
 	if err != nil {
 		return v1alpha1.Pool{}, err
 	}
 
-	resp, err := Pool.client.dynamicUpdateStatus(Pool.resourceId, m.Metadata.Name, jsonData)
+	result, err := Pool.client.dhcp.DhcpV1alpha1().Pools(m.Namespace).Patch(Pool.client.ctx, m.Name, types.MergePatchType, jsonData, metav1.PatchOptions{})
 	if err != nil {
-		return v1alpha1.Pool{}, err
+		return v1alpha1.Pool{}, wrapError(err)
 	}
 
-	var result v1alpha1.Pool
-	err = json.Unmarshal(resp, &result)
+	return *result, nil
+}
+
+// UpdateStatus replaces the pool status. The status is owned by the
+// operator, so on conflict it is written over a fresh copy of the pool.
+func (Pool *Pool) UpdateStatus(m v1alpha1.Pool) (v1alpha1.Pool, error) {
+	pools := Pool.client.dhcp.DhcpV1alpha1().Pools(m.Namespace)
+	status := m.Status
+
+	var result *v1alpha1.Pool
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() (err error) {
+		m.Status = status
+
+		result, err = pools.UpdateStatus(Pool.client.ctx, &m, metav1.UpdateOptions{})
+		if apierrors.IsConflict(err) {
+			fresh, getErr := pools.Get(Pool.client.ctx, m.Name, metav1.GetOptions{})
+			if getErr != nil {
+				return getErr
+			}
+
+			m = *fresh
+		}
+
+		return err
+	})
 	if err != nil {
-		return v1alpha1.Pool{}, err
+		return v1alpha1.Pool{}, wrapError(err)
 	}
 
-	return result, nil
+	return *result, nil
 }
@@ -1,48 +1,59 @@
 package kubernetes
 
 import (
-	"encoding/json"
+	"fmt"
 
 	"github.com/CRASH-Tech/dhcp-operator/cmd/kubernetes/api/v1alpha1"
-	"k8s.io/apimachinery/pkg/runtime/schema"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 type PXE struct {
-	client     *Client
-	resourceId schema.GroupVersionResource
+	client *Client
 }
 
-func (PXE *PXE) Get(name string) (v1alpha1.PXE, error) {
-	item, err := PXE.client.dynamicGet(PXE.resourceId, name)
-	if err != nil {
-		return v1alpha1.PXE{}, err
-	}
+func (PXE *PXE) Get(namespace, name string) (v1alpha1.PXE, error) {
+	var result *v1alpha1.PXE
+	err := withRetry(func() (err error) {
+		result, err = PXE.client.dhcp.DhcpV1alpha1().PXEs(namespace).Get(PXE.client.ctx, name, metav1.GetOptions{})
 
-	var result v1alpha1.PXE
-	err = json.Unmarshal(item, &result)
+		return err
+	})
 	if err != nil {
 		return v1alpha1.PXE{}, err
 	}
 
-	return result, nil
+	return *result, nil
 }
 
 func (PXE *PXE) GetAll() ([]v1alpha1.PXE, error) {
-	items, err := PXE.client.dynamicGetAll(PXE.resourceId)
-	if err != nil {
-		panic(err)
-	}
-
 	var result []v1alpha1.PXE
-	for _, item := range items {
-		var q v1alpha1.PXE
-		err = json.Unmarshal(item, &q)
+	for _, namespace := range PXE.client.namespaces {
+		var items *v1alpha1.PXEList
+		err := withRetry(func() (err error) {
+			items, err = PXE.client.dhcp.DhcpV1alpha1().PXEs(namespace).List(PXE.client.ctx, metav1.ListOptions{})
+
+			return err
+		})
 		if err != nil {
 			return nil, err
 		}
 
-		result = append(result, q)
+		result = append(result, items.Items...)
 	}
 
 	return result, nil
 }
+
+// Find looks up the PXE config by name in every watched namespace.
+func (PXE *PXE) Find(name string) (v1alpha1.PXE, error) {
+	for _, namespace := range PXE.client.namespaces {
+		result, err := PXE.Get(namespace, name)
+		if IsNotFound(err) {
+			continue
+		}
+
+		return result, err
+	}
+
+	return v1alpha1.PXE{}, fmt.Errorf("%w: pxe %s", ErrNotFound, name)
+}
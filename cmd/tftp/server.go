@@ -0,0 +1,386 @@
+// Package tftp implements a read-only TFTP server (RFC 1350) with the
+// blksize (RFC 2348), timeout and tsize (RFC 2349) options.
+package tftp
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	opRRQ   = 1
+	opWRQ   = 2
+	opDATA  = 3
+	opACK   = 4
+	opERROR = 5
+	opOACK  = 6
+
+	errNotDefined       = 0
+	errFileNotFound     = 1
+	errAccessViolation  = 2
+	errIllegalOperation = 4
+	errUnknownTID       = 5
+
+	defaultBlockSize = 512
+	minBlockSize     = 8
+	maxBlockSize     = 65464
+
+	defaultTimeout = 5 * time.Second
+	defaultRetries = 5
+)
+
+var (
+	ErrFileNotFound    = errors.New("tftp: file not found")
+	ErrAccessViolation = errors.New("tftp: access violation")
+	ErrServerClosed    = errors.New("tftp: server closed")
+)
+
+// ReadHandler opens the file requested by a client. The size is -1 when it
+// is not known in advance, then the tsize option is not acknowledged.
+type ReadHandler func(filename string, remote net.Addr) (io.ReadCloser, int64, error)
+
+// TransferHandler is called once a transfer finished, err is nil on success.
+type TransferHandler func(filename string, remote net.Addr, sent int64, err error)
+
+type Server struct {
+	Addr        string
+	Read        ReadHandler
+	Transferred TransferHandler
+	Timeout     time.Duration
+	Retries     int
+
+	mu     sync.Mutex
+	conn   *net.UDPConn
+	closed bool
+	wg     sync.WaitGroup
+}
+
+// ListenAndServe answers read requests until the server is shut down.
+func (s *Server) ListenAndServe() error {
+	addr, err := net.ResolveUDPAddr("udp", s.Addr)
+	if err != nil {
+		return err
+	}
+
+	conn, err := net.ListenUDP("udp", addr)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		conn.Close()
+
+		return ErrServerClosed
+	}
+	s.conn = conn
+	s.mu.Unlock()
+
+	buf := make([]byte, maxBlockSize)
+	for {
+		n, remote, err := conn.ReadFromUDP(buf)
+		if err != nil {
+			if s.isClosed() {
+				return ErrServerClosed
+			}
+
+			return err
+		}
+
+		packet := make([]byte, n)
+		copy(packet, buf[:n])
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.handle(packet, remote)
+		}()
+	}
+}
+
+// Shutdown stops accepting requests and waits for running transfers to stop.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	s.closed = true
+	conn := s.conn
+	s.mu.Unlock()
+
+	if conn != nil {
+		conn.Close()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *Server) isClosed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.closed
+}
+
+// handle serves a request from a new port, which is the transfer ID of the
+// server side.
+func (s *Server) handle(packet []byte, remote *net.UDPAddr) {
+	conn, err := net.ListenUDP("udp", nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	t := &transfer{
+		conn:    conn,
+		remote:  remote,
+		timeout: s.Timeout,
+		retries: s.Retries,
+	}
+	if t.timeout == 0 {
+		t.timeout = defaultTimeout
+	}
+	if t.retries == 0 {
+		t.retries = defaultRetries
+	}
+
+	if len(packet) < 2 {
+		return
+	}
+
+	switch binary.BigEndian.Uint16(packet) {
+	case opRRQ:
+		s.read(t, packet[2:])
+	case opWRQ:
+		t.sendError(errAccessViolation, "server is read-only")
+	default:
+		t.sendError(errIllegalOperation, "illegal operation")
+	}
+}
+
+func (s *Server) read(t *transfer, request []byte) {
+	filename, mode, options, err := parseRequest(request)
+	if err != nil {
+		t.sendError(errIllegalOperation, err.Error())
+
+		return
+	}
+
+	// netascii files are sent unchanged, boot loaders are binary anyway.
+	if mode != "octet" && mode != "netascii" {
+		t.sendError(errIllegalOperation, fmt.Sprintf("unsupported mode %s", mode))
+
+		return
+	}
+
+	file, size, err := s.Read(filename, t.remote)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrFileNotFound):
+			t.sendError(errFileNotFound, "file not found")
+		case errors.Is(err, ErrAccessViolation):
+			t.sendError(errAccessViolation, "access violation")
+		default:
+			t.sendError(errNotDefined, err.Error())
+		}
+		s.transferred(filename, t.remote, 0, err)
+
+		return
+	}
+	defer file.Close()
+
+	sent, err := t.send(file, size, options, s.isClosed)
+	s.transferred(filename, t.remote, sent, err)
+}
+
+func (s *Server) transferred(filename string, remote net.Addr, sent int64, err error) {
+	if s.Transferred != nil {
+		s.Transferred(filename, remote, sent, err)
+	}
+}
+
+type transfer struct {
+	conn    *net.UDPConn
+	remote  *net.UDPAddr
+	timeout time.Duration
+	retries int
+}
+
+// send negotiates the options and sends the file block by block, waiting for
+// the acknowledgement of every block.
+func (t *transfer) send(file io.Reader, size int64, options map[string]string, closed func() bool) (int64, error) {
+	blockSize := defaultBlockSize
+
+	var oack []string
+	if value, ok := options["blksize"]; ok {
+		n, err := strconv.Atoi(value)
+		if err == nil && n >= minBlockSize {
+			if n > maxBlockSize {
+				n = maxBlockSize
+			}
+			blockSize = n
+			oack = append(oack, "blksize", strconv.Itoa(n))
+		}
+	}
+
+	if value, ok := options["timeout"]; ok {
+		n, err := strconv.Atoi(value)
+		if err == nil && n >= 1 && n <= 255 {
+			t.timeout = time.Duration(n) * time.Second
+			oack = append(oack, "timeout", value)
+		}
+	}
+
+	if _, ok := options["tsize"]; ok && size >= 0 {
+		oack = append(oack, "tsize", strconv.FormatInt(size, 10))
+	}
+
+	if len(oack) > 0 {
+		packet := []byte{0, opOACK}
+		for _, field := range oack {
+			packet = append(packet, field...)
+			packet = append(packet, 0)
+		}
+
+		err := t.sendAndWait(packet, 0)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	var sent int64
+	buf := make([]byte, 4+blockSize)
+	for block := uint16(1); ; block++ {
+		if closed() {
+			t.sendError(errNotDefined, "server is shutting down")
+
+			return sent, ErrServerClosed
+		}
+
+		n, err := io.ReadFull(file, buf[4:])
+		last := err == io.EOF || err == io.ErrUnexpectedEOF
+		if err != nil && !last {
+			t.sendError(errNotDefined, "read error")
+
+			return sent, err
+		}
+
+		binary.BigEndian.PutUint16(buf, opDATA)
+		binary.BigEndian.PutUint16(buf[2:], block)
+
+		err = t.sendAndWait(buf[:4+n], block)
+		if err != nil {
+			return sent, err
+		}
+		sent += int64(n)
+
+		if last {
+			return sent, nil
+		}
+	}
+}
+
+// sendAndWait sends the packet until the block is acknowledged or the
+// retries are exhausted.
+func (t *transfer) sendAndWait(packet []byte, block uint16) error {
+	buf := make([]byte, maxBlockSize)
+
+	for attempt := 0; attempt <= t.retries; attempt++ {
+		_, err := t.conn.WriteToUDP(packet, t.remote)
+		if err != nil {
+			return err
+		}
+
+		deadline := time.Now().Add(t.timeout)
+		for {
+			t.conn.SetReadDeadline(deadline)
+
+			n, addr, err := t.conn.ReadFromUDP(buf)
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				break
+			}
+			if err != nil {
+				return err
+			}
+
+			if !addr.IP.Equal(t.remote.IP) || addr.Port != t.remote.Port {
+				t.sendErrorTo(addr, errUnknownTID, "unknown transfer id")
+
+				continue
+			}
+
+			if n < 4 {
+				continue
+			}
+
+			switch binary.BigEndian.Uint16(buf) {
+			case opACK:
+				// Duplicate acknowledgements are ignored, resending on them
+				// would double the traffic (Sorcerer's Apprentice).
+				if binary.BigEndian.Uint16(buf[2:]) == block {
+					return nil
+				}
+			case opERROR:
+				message, _, _ := bytes.Cut(buf[4:n], []byte{0})
+
+				return fmt.Errorf("tftp: client error %d: %s", binary.BigEndian.Uint16(buf[2:]), message)
+			}
+		}
+	}
+
+	return fmt.Errorf("tftp: timeout waiting for ack of block %d", block)
+}
+
+func (t *transfer) sendError(code uint16, message string) {
+	t.sendErrorTo(t.remote, code, message)
+}
+
+func (t *transfer) sendErrorTo(addr *net.UDPAddr, code uint16, message string) {
+	packet := make([]byte, 4, 5+len(message))
+	binary.BigEndian.PutUint16(packet, opERROR)
+	binary.BigEndian.PutUint16(packet[2:], code)
+	packet = append(packet, message...)
+	packet = append(packet, 0)
+
+	t.conn.WriteToUDP(packet, addr)
+}
+
+// parseRequest splits a request into filename, mode and options, the option
+// names and the mode are case insensitive.
+func parseRequest(request []byte) (string, string, map[string]string, error) {
+	fields := strings.Split(string(request), "\x00")
+	if len(fields) < 3 || fields[len(fields)-1] != "" {
+		return "", "", nil, errors.New("malformed request")
+	}
+	fields = fields[:len(fields)-1]
+
+	if fields[0] == "" {
+		return "", "", nil, errors.New("empty filename")
+	}
+
+	options := make(map[string]string)
+	for i := 2; i+1 < len(fields); i += 2 {
+		options[strings.ToLower(fields[i])] = fields[i+1]
+	}
+
+	return fields[0], strings.ToLower(fields[1]), options, nil
+}
@@ -0,0 +1,307 @@
+package tftp
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// startServer serves files from the map on a loopback port.
+func startServer(t *testing.T, files map[string][]byte) (*Server, *net.UDPAddr) {
+	t.Helper()
+
+	s := &Server{
+		Addr:    "127.0.0.1:0",
+		Timeout: 200 * time.Millisecond,
+		Retries: 3,
+		Read: func(filename string, remote net.Addr) (io.ReadCloser, int64, error) {
+			data, ok := files[filename]
+			if !ok {
+				return nil, 0, ErrFileNotFound
+			}
+
+			return io.NopCloser(bytes.NewReader(data)), int64(len(data)), nil
+		},
+	}
+
+	go s.ListenAndServe()
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		s.Shutdown(ctx)
+	})
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		s.mu.Lock()
+		conn := s.conn
+		s.mu.Unlock()
+
+		if conn != nil {
+			return s, conn.LocalAddr().(*net.UDPAddr)
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	t.Fatal("server did not start")
+
+	return nil, nil
+}
+
+type client struct {
+	t    *testing.T
+	conn *net.UDPConn
+	peer *net.UDPAddr
+}
+
+func newClient(t *testing.T) *client {
+	t.Helper()
+
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return &client{t: t, conn: conn}
+}
+
+func (c *client) readRequest(server *net.UDPAddr, filename string, options ...string) {
+	c.t.Helper()
+
+	packet := []byte{0, opRRQ}
+	for _, field := range append([]string{filename, "octet"}, options...) {
+		packet = append(packet, field...)
+		packet = append(packet, 0)
+	}
+
+	_, err := c.conn.WriteToUDP(packet, server)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+}
+
+// receive returns the next packet, the transfer port of the server is
+// remembered from the first one.
+func (c *client) receive() []byte {
+	c.t.Helper()
+
+	buf := make([]byte, maxBlockSize+4)
+	c.conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	n, addr, err := c.conn.ReadFromUDP(buf)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+
+	if c.peer == nil {
+		c.peer = addr
+	}
+
+	return buf[:n]
+}
+
+func (c *client) ack(block uint16) {
+	c.t.Helper()
+
+	packet := make([]byte, 4)
+	binary.BigEndian.PutUint16(packet, opACK)
+	binary.BigEndian.PutUint16(packet[2:], block)
+
+	_, err := c.conn.WriteToUDP(packet, c.peer)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+}
+
+func expectData(t *testing.T, packet []byte, block uint16) []byte {
+	t.Helper()
+
+	if len(packet) < 4 || binary.BigEndian.Uint16(packet) != opDATA {
+		t.Fatalf("expected DATA, got %v", packet)
+	}
+
+	if got := binary.BigEndian.Uint16(packet[2:]); got != block {
+		t.Fatalf("expected block %d, got %d", block, got)
+	}
+
+	return packet[4:]
+}
+
+func parseOACK(t *testing.T, packet []byte) map[string]string {
+	t.Helper()
+
+	if len(packet) < 2 || binary.BigEndian.Uint16(packet) != opOACK {
+		t.Fatalf("expected OACK, got %v", packet)
+	}
+
+	fields := strings.Split(strings.TrimSuffix(string(packet[2:]), "\x00"), "\x00")
+	options := make(map[string]string)
+	for i := 0; i+1 < len(fields); i += 2 {
+		options[fields[i]] = fields[i+1]
+	}
+
+	return options
+}
+
+func TestReadDefaultBlockSize(t *testing.T) {
+	data := bytes.Repeat([]byte("x"), 2*defaultBlockSize)
+	_, server := startServer(t, map[string][]byte{"file": data})
+
+	c := newClient(t)
+	c.readRequest(server, "file")
+
+	// A file of whole blocks ends with an empty block.
+	var got []byte
+	for block := uint16(1); ; block++ {
+		payload := expectData(t, c.receive(), block)
+		got = append(got, payload...)
+		c.ack(block)
+
+		if len(payload) < defaultBlockSize {
+			break
+		}
+	}
+
+	if !bytes.Equal(got, data) {
+		t.Fatalf("expected %d bytes, got %d", len(data), len(got))
+	}
+}
+
+func TestReadOptionNegotiation(t *testing.T) {
+	data := bytes.Repeat([]byte("0123456789"), 300)
+	_, server := startServer(t, map[string][]byte{"file": data})
+
+	c := newClient(t)
+	c.readRequest(server, "file", "BLKSIZE", "1024", "tsize", "0", "unknown", "1")
+
+	options := parseOACK(t, c.receive())
+	if options["blksize"] != "1024" {
+		t.Fatalf("expected blksize 1024, got %q", options["blksize"])
+	}
+	if options["tsize"] != strconv.Itoa(len(data)) {
+		t.Fatalf("expected tsize %d, got %q", len(data), options["tsize"])
+	}
+	if _, ok := options["unknown"]; ok {
+		t.Fatal("unknown option acknowledged")
+	}
+	c.ack(0)
+
+	var got []byte
+	for block := uint16(1); ; block++ {
+		payload := expectData(t, c.receive(), block)
+		got = append(got, payload...)
+		c.ack(block)
+
+		if len(payload) < 1024 {
+			break
+		}
+	}
+
+	if !bytes.Equal(got, data) {
+		t.Fatalf("expected %d bytes, got %d", len(data), len(got))
+	}
+}
+
+func TestReadBlockNumberWraparound(t *testing.T) {
+	const blockSize = minBlockSize
+	data := make([]byte, blockSize*(1<<16)+3)
+	for i := range data {
+		data[i] = byte(i / blockSize)
+	}
+	_, server := startServer(t, map[string][]byte{"file": data})
+
+	c := newClient(t)
+	c.readRequest(server, "file", "blksize", strconv.Itoa(blockSize))
+
+	parseOACK(t, c.receive())
+	c.ack(0)
+
+	var got []byte
+	block := uint16(1)
+	for {
+		payload := expectData(t, c.receive(), block)
+		got = append(got, payload...)
+		c.ack(block)
+
+		if len(payload) < blockSize {
+			break
+		}
+		block++
+	}
+
+	if block != 1 {
+		t.Fatalf("expected the last block to wrap to 1, got %d", block)
+	}
+	if !bytes.Equal(got, data) {
+		t.Fatalf("expected %d bytes, got %d", len(data), len(got))
+	}
+}
+
+func TestReadRetransmitAfterLostAck(t *testing.T) {
+	data := []byte("short file")
+	_, server := startServer(t, map[string][]byte{"file": data})
+
+	c := newClient(t)
+	c.readRequest(server, "file")
+
+	first := expectData(t, c.receive(), 1)
+
+	// The ACK is lost, the server sends the block again after its timeout.
+	second := expectData(t, c.receive(), 1)
+	if !bytes.Equal(first, second) || !bytes.Equal(first, data) {
+		t.Fatalf("expected %q twice, got %q and %q", data, first, second)
+	}
+	c.ack(1)
+}
+
+func TestReadUnknownTransferID(t *testing.T) {
+	data := bytes.Repeat([]byte("x"), defaultBlockSize+1)
+	_, server := startServer(t, map[string][]byte{"file": data})
+
+	c := newClient(t)
+	c.readRequest(server, "file")
+	expectData(t, c.receive(), 1)
+
+	stranger := newClient(t)
+	stranger.peer = c.peer
+	stranger.ack(1)
+
+	packet := stranger.receive()
+	if binary.BigEndian.Uint16(packet) != opERROR || binary.BigEndian.Uint16(packet[2:]) != errUnknownTID {
+		t.Fatalf("expected unknown transfer id error, got %v", packet)
+	}
+
+	// The transfer with the real client is not disturbed, a retransmit of
+	// block 1 may still be on the way.
+	c.ack(1)
+	packet = c.receive()
+	if binary.BigEndian.Uint16(packet[2:]) == 1 {
+		packet = c.receive()
+	}
+	payload := expectData(t, packet, 2)
+	if len(payload) != 1 {
+		t.Fatalf("expected 1 byte, got %d", len(payload))
+	}
+	c.ack(2)
+}
+
+func TestReadFileNotFound(t *testing.T) {
+	_, server := startServer(t, nil)
+
+	c := newClient(t)
+	c.readRequest(server, "missing")
+
+	packet := c.receive()
+	if binary.BigEndian.Uint16(packet) != opERROR || binary.BigEndian.Uint16(packet[2:]) != errFileNotFound {
+		t.Fatalf("expected file not found error, got %v", packet)
+	}
+}
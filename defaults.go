@@ -0,0 +1,60 @@
+package main
+
+import (
+	"net"
+	"strings"
+
+	"github.com/CRASH-Tech/dhcp-operator/cmd/kubernetes/api/v1alpha1"
+)
+
+const defaultLeaseDuration = "1h"
+
+type patchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+func defaultPool(pool v1alpha1.Pool) []patchOperation {
+	var result []patchOperation
+
+	if pool.Spec.Lease == "" {
+		result = append(result, patchOperation{Op: "add", Path: "/spec/lease", Value: defaultLeaseDuration})
+	}
+
+	if pool.Spec.Hostname.Conflict == "" {
+		result = append(result, patchOperation{Op: "add", Path: "/spec/hostname", Value: v1alpha1.PoolHostname{
+			Sanitize: pool.Spec.Hostname.Sanitize,
+			Template: pool.Spec.Hostname.Template,
+			Conflict: hostnameConflictAllow,
+		}})
+	}
+
+	return result
+}
+
+func defaultLease(lease v1alpha1.Lease, pools []v1alpha1.Pool) []patchOperation {
+	var result []patchOperation
+
+	if lease.Name == "" && lease.GenerateName == "" && lease.Spec.Ip != "" {
+		result = append(result, patchOperation{Op: "add", Path: "/metadata/name", Value: lease.Spec.Ip})
+	}
+
+	mac := strings.ToUpper(lease.Spec.Mac)
+	if mac != lease.Spec.Mac {
+		result = append(result, patchOperation{Op: "replace", Path: "/spec/mac", Value: mac})
+	}
+
+	ip := net.ParseIP(lease.Spec.Ip)
+	if lease.Spec.Pool == "" && ip != nil {
+		for _, pool := range pools {
+			if pool.Namespace == lease.Namespace && isIPInPool(ip, pool) {
+				result = append(result, patchOperation{Op: "add", Path: "/spec/pool", Value: pool.Name})
+
+				break
+			}
+		}
+	}
+
+	return result
+}
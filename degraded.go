@@ -0,0 +1,104 @@
+package main
+
+import (
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/CRASH-Tech/dhcp-operator/cmd/kubernetes/api/v1alpha1"
+	"github.com/insomniacslk/dhcp/dhcpv4"
+	log "github.com/sirupsen/logrus"
+)
+
+// degradedLeaseTime is the minimal lease time given while the API is down.
+// It never runs past the lease cleaner grace period, so the lease survives
+// until the client renews against a healthy API.
+const degradedLeaseTime = 5 * time.Minute
+
+// leaseCleanerGrace is how long an expired lease is kept before the cleaner
+// deletes it.
+const leaseCleanerGrace = 5 * time.Minute
+
+// serveDegraded answers clients which already hold a lease from the cache
+// while the Kubernetes API is unavailable. Nothing is written and new
+// allocations are refused.
+func serveDegraded(conn net.PacketConn, peer net.Addr, msg dhcpv4.DHCPv4, msgType dhcpv4.MessageType) {
+	lease, found := getCachedLease(msg)
+	if !found {
+		log.Warn("Kubernetes API unavailable, refuse new allocation:\n", msg.Summary())
+
+		return
+	}
+
+	pool, err := kClient.V1alpha1().Pool().GetCached(lease.Namespace, lease.Spec.Pool)
+	if err != nil {
+		log.Error(err)
+
+		return
+	}
+
+	duration := degradedLeaseTime
+	if !lease.Spec.Static {
+		ends, err := strconv.ParseInt(lease.Status.Ends, 10, 64)
+		if err != nil {
+			log.Warnf("Kubernetes API unavailable, lease %s has no end, refuse it", lease.Name)
+
+			return
+		}
+
+		// The lease must not outlive the cleaner, which deletes it once the
+		// API is back and the address could be handed out twice.
+		until := time.Until(time.Unix(ends, 0)).Truncate(time.Second)
+		deleted := time.Until(time.Unix(ends, 0).Add(leaseCleanerGrace)).Truncate(time.Second)
+		if deleted <= 0 {
+			log.Warnf("Kubernetes API unavailable, cached lease %s expired, refuse it", lease.Name)
+
+			return
+		}
+
+		if until > duration {
+			duration = until
+		}
+		if deleted < duration {
+			duration = deleted
+		}
+	}
+
+	reply, err := dhcpv4.NewReplyFromRequest(&msg)
+	if err != nil {
+		log.Error(err)
+
+		return
+	}
+
+	reply, err = buildReply(&msg, reply, lease, pool, msgType, duration)
+	if err != nil {
+		log.Error(err)
+
+		return
+	}
+
+	log.Warnf("Kubernetes API unavailable, serve cached lease IP: %s MAC: %s", lease.Spec.Ip, lease.Spec.Mac)
+	err = sendReply(conn, peer, reply)
+	if err != nil {
+		log.Error(err)
+	}
+}
+
+func getCachedLease(msg dhcpv4.DHCPv4) (v1alpha1.Lease, bool) {
+	leases, err := kClient.V1alpha1().Lease().GetAllCached()
+	if err != nil {
+		log.Error(err)
+
+		return v1alpha1.Lease{}, false
+	}
+
+	for _, lease := range leases {
+		if strings.EqualFold(lease.Spec.Mac, msg.ClientHWAddr.String()) {
+			return lease, true
+		}
+	}
+
+	return v1alpha1.Lease{}, false
+}
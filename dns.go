@@ -0,0 +1,214 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/CRASH-Tech/dhcp-operator/cmd/kubernetes/api/externaldns"
+	"github.com/CRASH-Tech/dhcp-operator/cmd/kubernetes/api/v1alpha1"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	dnsModeEndpoint = "dnsendpoint"
+	dnsModeHosts    = "hosts"
+
+	dnsEndpointLabel = "dhcp.xfix.org/pool"
+)
+
+type dnsRecord struct {
+	ip   string
+	fqdn string
+	pool string
+}
+
+// dnsSync makes the DNSEndpoints match the active leases.
+func dnsSync() error {
+	if config.Dns.Mode != dnsModeEndpoint {
+		return nil
+	}
+
+	log.Debug("Start DNS sync...")
+
+	records, err := getDnsRecords()
+	if err != nil {
+		return err
+	}
+
+	desired := make(map[string][]externaldns.Endpoint)
+	for _, record := range records {
+		desired[record.pool] = append(desired[record.pool],
+			externaldns.Endpoint{
+				DNSName:    record.fqdn,
+				Targets:    []string{record.ip},
+				RecordType: "A",
+				RecordTTL:  config.Dns.TTL,
+			},
+			externaldns.Endpoint{
+				DNSName:    reverseName(net.ParseIP(record.ip)),
+				Targets:    []string{record.fqdn},
+				RecordType: "PTR",
+				RecordTTL:  config.Dns.TTL,
+			},
+		)
+	}
+
+	client := kClient.ExternalDNS().DNSEndpoint(getDnsNamespace())
+	existing, err := client.GetAll(dnsEndpointLabel)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+
+	for _, dnsEndpoint := range existing {
+		pool := dnsEndpoint.Labels[dnsEndpointLabel]
+		endpoints, found := desired[pool]
+		if !found {
+			log.Infof("Delete DNS endpoint: %s", dnsEndpoint.Name)
+			err := client.Delete(dnsEndpoint)
+			if err != nil {
+				errs = append(errs, err)
+			}
+
+			continue
+		}
+		delete(desired, pool)
+
+		if reflect.DeepEqual(dnsEndpoint.Spec.Endpoints, endpoints) {
+			continue
+		}
+
+		log.Debugf("Update DNS endpoint: %s", dnsEndpoint.Name)
+		dnsEndpoint.Spec.Endpoints = endpoints
+		_, err := client.PatchSpec(dnsEndpoint)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	for pool, endpoints := range desired {
+		dnsEndpoint := externaldns.DNSEndpoint{}
+		dnsEndpoint.Name = fmt.Sprintf("dhcp-%s", pool)
+		dnsEndpoint.Labels = map[string]string{dnsEndpointLabel: pool}
+		dnsEndpoint.Spec.Endpoints = endpoints
+
+		log.Infof("Create DNS endpoint: %s", dnsEndpoint.Name)
+		_, err := client.Create(dnsEndpoint)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func hostsHandler(w http.ResponseWriter, r *http.Request) {
+	records, err := getDnsRecords()
+	if err != nil {
+		log.Error(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte("Internal error!"))
+
+		return
+	}
+
+	var b strings.Builder
+	for _, record := range records {
+		fmt.Fprintf(&b, "%s\t%s\n", record.ip, record.fqdn)
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(b.String()))
+}
+
+func getDnsRecords() ([]dnsRecord, error) {
+	var result []dnsRecord
+
+	pools, err := kClient.V1alpha1().Pool().GetAllCached()
+	if err != nil {
+		return result, err
+	}
+
+	domains := make(map[string]string)
+	for _, pool := range pools {
+		domains[pool.Namespace+"/"+pool.Name] = strings.Trim(pool.Spec.Domain, ".")
+	}
+
+	leases, err := kClient.V1alpha1().Lease().GetAllCached()
+	if err != nil {
+		return result, err
+	}
+
+	for _, lease := range leases {
+		domain := domains[lease.Namespace+"/"+lease.Spec.Pool]
+		if lease.Status.Hostname == "" || domain == "" || !isLeaseActive(lease) {
+			continue
+		}
+
+		result = append(result, dnsRecord{
+			ip:   lease.Spec.Ip,
+			fqdn: strings.ToLower(fmt.Sprintf("%s.%s", lease.Status.Hostname, domain)),
+			pool: dnsPoolKey(lease),
+		})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].fqdn < result[j].fqdn
+	})
+
+	return result, nil
+}
+
+func isLeaseActive(lease v1alpha1.Lease) bool {
+	if lease.DeletionTimestamp != nil {
+		return false
+	}
+
+	if lease.Spec.Static {
+		return true
+	}
+
+	ends, err := strconv.ParseInt(lease.Status.Ends, 10, 64)
+	if err != nil {
+		return false
+	}
+
+	return time.Unix(ends, 0).After(time.Now())
+}
+
+func reverseName(ip net.IP) string {
+	ip4 := ip.To4()
+	if ip4 == nil {
+		return ""
+	}
+
+	return fmt.Sprintf("%d.%d.%d.%d.in-addr.arpa", ip4[3], ip4[2], ip4[1], ip4[0])
+}
+
+func getDnsNamespace() string {
+	if config.Dns.Namespace != "" {
+		return config.Dns.Namespace
+	}
+
+	return namespace
+}
+
+// dnsPoolKey identifies the pool of the lease, qualified by the namespace
+// when running namespace scoped. Namespaces cannot contain dots, so the key
+// is unique for every namespace and pool.
+func dnsPoolKey(lease v1alpha1.Lease) string {
+	if lease.Namespace == "" {
+		return lease.Spec.Pool
+	}
+
+	return fmt.Sprintf("%s.%s", lease.Namespace, lease.Spec.Pool)
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+
+	"github.com/CRASH-Tech/dhcp-operator/cmd/kubernetes/api/v1alpha1"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+const (
+	eventLeaseGranted     = "LeaseGranted"
+	eventLeaseRenewed     = "LeaseRenewed"
+	eventLeaseReleased    = "LeaseReleased"
+	eventLeaseExpired     = "LeaseExpired"
+	eventLeaseDeclined    = "LeaseDeclined"
+	eventPoolExhausted    = "PoolExhausted"
+	eventConflictDetected = "ConflictDetected"
+)
+
+var recorder record.EventRecorder
+
+// startEventRecorder sends events about Pools and Leases to the API server
+// until ctx is cancelled, so they show up in kubectl describe.
+func startEventRecorder(ctx context.Context) {
+	scheme := runtime.NewScheme()
+	err := v1alpha1.AddToScheme(scheme)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	broadcaster := record.NewBroadcaster()
+	broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{
+		Interface: config.KubernetesClient.CoreV1().Events(""),
+	})
+
+	recorder = broadcaster.NewRecorder(scheme, corev1.EventSource{
+		Component: "dhcp-operator",
+		Host:      hostname,
+	})
+
+	go func() {
+		<-ctx.Done()
+		broadcaster.Shutdown()
+	}()
+}
+
+func leaseEvent(lease v1alpha1.Lease, eventType, reason, messageFmt string, args ...interface{}) {
+	recorder.Eventf(&lease, eventType, reason, messageFmt, args...)
+}
+
+func poolEvent(pool v1alpha1.Pool, eventType, reason, messageFmt string, args ...interface{}) {
+	recorder.Eventf(&pool, eventType, reason, messageFmt, args...)
+}
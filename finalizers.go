@@ -0,0 +1,162 @@
+package main
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/CRASH-Tech/dhcp-operator/cmd/kubernetes/api/v1alpha1"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+const (
+	poolFinalizer  = "dhcp.xfix.org/pool-drain"
+	leaseFinalizer = "dhcp.xfix.org/lease-cleanup"
+
+	forceDeleteAnnotation = "dhcp.xfix.org/force-delete"
+
+	eventPoolDraining = "PoolDraining"
+)
+
+var errPoolDraining = errors.New("pool is draining")
+
+// finalizerSync adds the finalizers to pools and leases, drains deleted pools
+// and cleans up after deleted leases.
+func finalizerSync() {
+	log.Debug("Start finalizer sync...")
+
+	leases, err := kClient.V1alpha1().Lease().GetAll()
+	if err != nil {
+		log.Error(err)
+
+		return
+	}
+
+	pools, err := kClient.V1alpha1().Pool().GetAll()
+	if err != nil {
+		log.Error(err)
+
+		return
+	}
+
+	for _, pool := range pools {
+		if pool.DeletionTimestamp == nil {
+			ensureFinalizer(pool.Finalizers, poolFinalizer, func(finalizers []string) error {
+				_, err := kClient.V1alpha1().Pool().SetFinalizers(pool, finalizers)
+
+				return err
+			})
+
+			continue
+		}
+
+		drainPool(pool, leases)
+	}
+
+	var deleted []v1alpha1.Lease
+	for _, lease := range leases {
+		if lease.DeletionTimestamp == nil {
+			ensureFinalizer(lease.Finalizers, leaseFinalizer, func(finalizers []string) error {
+				_, err := kClient.V1alpha1().Lease().SetFinalizers(lease, finalizers)
+
+				return err
+			})
+
+			continue
+		}
+
+		if sets.New(lease.Finalizers...).Has(leaseFinalizer) {
+			deleted = append(deleted, lease)
+		}
+	}
+
+	if len(deleted) == 0 {
+		return
+	}
+
+	// Deleted leases are not active anymore, so a DNS sync drops their records.
+	err = dnsSync()
+	if err != nil {
+		log.Error(err)
+
+		return
+	}
+
+	for _, lease := range deleted {
+		leaseExpiration.DeleteLabelValues(lease.Spec.Ip, lease.Spec.Mac, lease.Spec.Pool, lease.Status.Hostname)
+
+		_, err := kClient.V1alpha1().Lease().SetFinalizers(lease, removeFinalizer(lease.Finalizers, leaseFinalizer))
+		if err != nil {
+			log.Error(err)
+
+			continue
+		}
+		log.Debugf("Lease %s cleaned up", lease.Name)
+	}
+}
+
+// drainPool keeps a deleted pool until its dynamic leases expired, so clients
+// do not lose addresses they still hold. The force annotation skips the wait.
+func drainPool(pool v1alpha1.Pool, leases []v1alpha1.Lease) {
+	if !sets.New(pool.Finalizers...).Has(poolFinalizer) {
+		return
+	}
+
+	var active int
+	for _, lease := range leases {
+		if lease.Namespace == pool.Namespace && lease.Spec.Pool == pool.Name && !lease.Spec.Static && isLeaseActive(lease) {
+			active++
+		}
+	}
+
+	if active > 0 && pool.Annotations[forceDeleteAnnotation] != "true" {
+		log.Infof("Pool %s is draining, %d active leases left", pool.Name, active)
+		poolEvent(pool, corev1.EventTypeNormal, eventPoolDraining, "Waiting for %d active leases to expire", active)
+
+		return
+	}
+
+	log.Infof("Pool %s is drained, remove finalizer", pool.Name)
+	_, err := kClient.V1alpha1().Pool().SetFinalizers(pool, removeFinalizer(pool.Finalizers, poolFinalizer))
+	if err != nil {
+		log.Error(err)
+	}
+}
+
+func isPoolDraining(pool v1alpha1.Pool) bool {
+	return pool.DeletionTimestamp != nil
+}
+
+// leaseRemaining is the time left until the lease ends, in whole seconds.
+func leaseRemaining(lease v1alpha1.Lease) time.Duration {
+	ends, err := strconv.ParseInt(lease.Status.Ends, 10, 64)
+	if err != nil {
+		return 0
+	}
+
+	return time.Until(time.Unix(ends, 0)).Truncate(time.Second)
+}
+
+func ensureFinalizer(finalizers []string, finalizer string, set func([]string) error) {
+	if sets.New(finalizers...).Has(finalizer) {
+		return
+	}
+
+	err := set(append(finalizers, finalizer))
+	if err != nil {
+		log.Error(err)
+	}
+}
+
+func removeFinalizer(finalizers []string, finalizer string) []string {
+	var result []string
+	for _, f := range finalizers {
+		if f != finalizer {
+			result = append(result, f)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,217 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	haModeLeader       = "leader"
+	haModeActiveActive = "active-active"
+
+	memberLabel         = "dhcp.xfix.org/member"
+	memberLeaseDuration = 15
+	memberRenewPeriod   = 5 * time.Second
+	hashBuckets         = 256
+)
+
+var members struct {
+	sync.RWMutex
+	peers []string
+}
+
+func isActiveActive() bool {
+	return config.Ha.Mode == haModeActiveActive
+}
+
+// runMembership keeps a heartbeat lease for this replica and refreshes the
+// list of live peers, which is used to split clients between replicas. The
+// heartbeat lease is deleted once ctx is cancelled, so the peers take over
+// the clients of this replica right away.
+func runMembership(ctx context.Context) {
+	ticker := time.NewTicker(memberRenewPeriod)
+	defer ticker.Stop()
+
+	for {
+		err := renewMember(ctx)
+		if err != nil {
+			log.Error(err)
+		}
+
+		err = refreshMembers(ctx)
+		if err != nil {
+			log.Error(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			releaseMember()
+
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func releaseMember() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := config.KubernetesClient.CoordinationV1().Leases(namespace).Delete(ctx, memberLeaseName(hostname), metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		log.Error(err)
+
+		return
+	}
+
+	log.Info("Released member lease")
+}
+
+func memberLeaseName(member string) string {
+	return fmt.Sprintf("%s-member-%s", config.LeaderElection.LeaseName, member)
+}
+
+func renewMember(ctx context.Context) error {
+	leases := config.KubernetesClient.CoordinationV1().Leases(namespace)
+	name := memberLeaseName(hostname)
+	now := metav1.NewMicroTime(time.Now())
+	duration := int32(memberLeaseDuration)
+
+	lease, err := leases.Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		lease = &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+				Labels:    map[string]string{memberLabel: "true"},
+			},
+			Spec: coordinationv1.LeaseSpec{
+				HolderIdentity:       &hostname,
+				LeaseDurationSeconds: &duration,
+				AcquireTime:          &now,
+				RenewTime:            &now,
+			},
+		}
+
+		_, err = leases.Create(ctx, lease, metav1.CreateOptions{})
+
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	lease.Spec.HolderIdentity = &hostname
+	lease.Spec.LeaseDurationSeconds = &duration
+	lease.Spec.RenewTime = &now
+
+	_, err = leases.Update(ctx, lease, metav1.UpdateOptions{})
+
+	return err
+}
+
+// refreshMembers reads the live peers. The primary replica deletes the
+// heartbeat leases of replicas which are gone, like pods of old rollouts.
+func refreshMembers(ctx context.Context) error {
+	client := config.KubernetesClient.CoordinationV1().Leases(namespace)
+
+	leases, err := client.List(ctx, metav1.ListOptions{LabelSelector: memberLabel})
+	if err != nil {
+		return err
+	}
+
+	var peers []string
+	var expired []coordinationv1.Lease
+	for _, lease := range leases.Items {
+		if lease.Spec.HolderIdentity == nil || lease.Spec.RenewTime == nil || lease.Spec.LeaseDurationSeconds == nil {
+			continue
+		}
+
+		expires := lease.Spec.RenewTime.Add(time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second)
+		if expires.After(time.Now()) {
+			peers = append(peers, *lease.Spec.HolderIdentity)
+		} else {
+			expired = append(expired, lease)
+		}
+	}
+	sort.Strings(peers)
+
+	members.Lock()
+	if fmt.Sprint(peers) != fmt.Sprint(members.peers) {
+		log.Infof("Active replicas: %v", peers)
+	}
+	members.peers = peers
+	members.Unlock()
+
+	if !isPrimaryReplica() {
+		return nil
+	}
+
+	for _, lease := range expired {
+		log.Infof("Delete expired member lease: %s", lease.Name)
+		err = client.Delete(ctx, lease.Name, metav1.DeleteOptions{
+			Preconditions: &metav1.Preconditions{ResourceVersion: &lease.ResourceVersion},
+		})
+		if err != nil && !apierrors.IsNotFound(err) && !apierrors.IsConflict(err) {
+			log.Error(err)
+		}
+	}
+
+	return nil
+}
+
+// isResponsible reports whether this replica serves the client. The client
+// MAC is hashed with FNV-32a into hashBuckets buckets, which are spread over
+// the sorted live replicas by bucket modulo the number of replicas.
+func isResponsible(chaddr net.HardwareAddr) bool {
+	if !isActiveActive() {
+		return true
+	}
+
+	members.RLock()
+	defer members.RUnlock()
+
+	index := -1
+	for i, peer := range members.peers {
+		if peer == hostname {
+			index = i
+
+			break
+		}
+	}
+	if index < 0 {
+		return false
+	}
+
+	return int(hashBucket(chaddr))%len(members.peers) == index
+}
+
+// isPrimaryReplica reports whether this replica runs the cluster-wide
+// housekeeping like lease cleanup and DNS sync.
+func isPrimaryReplica() bool {
+	if !isActiveActive() {
+		return true
+	}
+
+	members.RLock()
+	defer members.RUnlock()
+
+	return len(members.peers) > 0 && members.peers[0] == hostname
+}
+
+func hashBucket(chaddr net.HardwareAddr) uint8 {
+	h := fnv.New32a()
+	h.Write(chaddr)
+
+	return uint8(h.Sum32() % hashBuckets)
+}
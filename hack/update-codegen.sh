@@ -0,0 +1,47 @@
+#!/usr/bin/env bash
+
+# Regenerates deepcopy functions, the typed clientset, listers and informers
+# for the dhcp.xfix.org API group. Requires k8s.io/code-generator binaries
+# (deepcopy-gen, client-gen, lister-gen, informer-gen) in PATH.
+
+set -o errexit
+set -o nounset
+set -o pipefail
+
+SCRIPT_ROOT=$(cd "$(dirname "${BASH_SOURCE[0]}")/.." && pwd)
+MODULE=github.com/CRASH-Tech/dhcp-operator
+APIS_PKG=${MODULE}/cmd/kubernetes/api/v1alpha1
+OUTPUT_PKG=${MODULE}/cmd/kubernetes/generated
+BOILERPLATE=${SCRIPT_ROOT}/hack/boilerplate.go.txt
+OUTPUT_BASE=$(mktemp -d)
+trap 'rm -rf "${OUTPUT_BASE}"' EXIT
+
+deepcopy-gen \
+  --input-dirs "${APIS_PKG}" \
+  --output-file-base zz_generated.deepcopy \
+  --output-base "${OUTPUT_BASE}" \
+  --go-header-file "${BOILERPLATE}"
+
+client-gen \
+  --clientset-name versioned \
+  --input-base "" \
+  --input "${APIS_PKG}" \
+  --output-package "${OUTPUT_PKG}/clientset" \
+  --output-base "${OUTPUT_BASE}" \
+  --go-header-file "${BOILERPLATE}"
+
+lister-gen \
+  --input-dirs "${APIS_PKG}" \
+  --output-package "${OUTPUT_PKG}/listers" \
+  --output-base "${OUTPUT_BASE}" \
+  --go-header-file "${BOILERPLATE}"
+
+informer-gen \
+  --input-dirs "${APIS_PKG}" \
+  --versioned-clientset-package "${OUTPUT_PKG}/clientset/versioned" \
+  --listers-package "${OUTPUT_PKG}/listers" \
+  --output-package "${OUTPUT_PKG}/informers" \
+  --output-base "${OUTPUT_BASE}" \
+  --go-header-file "${BOILERPLATE}"
+
+cp -r "${OUTPUT_BASE}/${MODULE}/." "${SCRIPT_ROOT}/"
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/CRASH-Tech/dhcp-operator/cmd/kubernetes/api/v1alpha1"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	hostnameConflictAllow    = "allow"
+	hostnameConflictGenerate = "generate"
+	hostnameConflictDrop     = "drop"
+
+	maxHostnameLength = 63
+)
+
+// resolveHostname picks the hostname for a lease. A hostname set on the lease
+// spec (a reservation) always wins, then the one sent by the client and finally
+// the pool template.
+func resolveHostname(pool v1alpha1.Pool, lease v1alpha1.Lease, requested string) string {
+	if lease.Spec.Hostname != "" {
+		return lease.Spec.Hostname
+	}
+
+	hostname := requested
+	if pool.Spec.Hostname.Sanitize {
+		hostname = sanitizeHostname(hostname)
+	}
+
+	if hostname == "" {
+		hostname = generateHostname(pool, lease)
+	}
+
+	if hostname == "" || !isHostnameTaken(hostname, lease) {
+		return hostname
+	}
+
+	var result string
+	switch pool.Spec.Hostname.Conflict {
+	case hostnameConflictGenerate:
+		log.Warnf("Hostname %s already used, generate new one for lease: %s", hostname, lease.Name)
+		result = generateHostname(pool, lease)
+	case hostnameConflictDrop:
+		log.Warnf("Hostname %s already used, drop it for lease: %s", hostname, lease.Name)
+	default:
+		log.Warnf("Hostname %s already used by another lease, lease: %s", hostname, lease.Name)
+		result = hostname
+	}
+
+	// Renewals keep the hostname, the conflict is reported once it changes.
+	if !strings.EqualFold(result, lease.Status.Hostname) {
+		leaseEvent(lease, corev1.EventTypeWarning, eventConflictDetected, "Hostname %s is already used by another lease", hostname)
+	}
+
+	return result
+}
+
+func sanitizeHostname(hostname string) string {
+	hostname, _, _ = strings.Cut(strings.ToLower(hostname), ".")
+
+	var b strings.Builder
+	for _, c := range hostname {
+		if (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') || c == '-' {
+			b.WriteRune(c)
+		} else {
+			b.WriteRune('-')
+		}
+	}
+
+	result := b.String()
+	if len(result) > maxHostnameLength {
+		result = result[:maxHostnameLength]
+	}
+
+	return strings.Trim(result, "-")
+}
+
+func generateHostname(pool v1alpha1.Pool, lease v1alpha1.Lease) string {
+	if pool.Spec.Hostname.Template == "" {
+		return ""
+	}
+
+	replacer := strings.NewReplacer(
+		"{{pool}}", pool.Name,
+		"{{ip-dashed}}", strings.ReplaceAll(lease.Spec.Ip, ".", "-"),
+		"{{mac}}", strings.ReplaceAll(lease.Spec.Mac, ":", ""),
+		"{{mac-dashed}}", strings.ReplaceAll(lease.Spec.Mac, ":", "-"),
+	)
+
+	return sanitizeHostname(replacer.Replace(pool.Spec.Hostname.Template))
+}
+
+func isHostnameTaken(hostname string, lease v1alpha1.Lease) bool {
+	leases, err := kClient.V1alpha1().Lease().GetAllCached()
+	if err != nil {
+		log.Error(err)
+
+		return false
+	}
+
+	for _, l := range leases {
+		if l.Namespace == lease.Namespace && l.Name == lease.Name {
+			continue
+		}
+
+		if strings.EqualFold(l.Status.Hostname, hostname) || strings.EqualFold(l.Spec.Hostname, hostname) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/CRASH-Tech/dhcp-operator/cmd/kubernetes/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSanitizeHostname(t *testing.T) {
+	tests := []struct {
+		name     string
+		hostname string
+		want     string
+	}{
+		{"valid", "worker-1", "worker-1"},
+		{"upper case", "Worker-1", "worker-1"},
+		{"domain is cut", "worker-1.example.org", "worker-1"},
+		{"invalid characters", "my_host name!", "my-host-name"},
+		{"leading and trailing dashes", "-_worker_-", "worker"},
+		{"unicode", "hôst", "h-st"},
+		{"only invalid characters", "___", ""},
+		{"empty", "", ""},
+		{"overlong", strings.Repeat("a", 100), strings.Repeat("a", maxHostnameLength)},
+		{"overlong ends with dash", strings.Repeat("a", 62) + "_b", strings.Repeat("a", 62)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := sanitizeHostname(tt.hostname)
+			if got != tt.want {
+				t.Errorf("sanitizeHostname(%q) = %q, want %q", tt.hostname, got, tt.want)
+			}
+
+			if len(got) > maxHostnameLength {
+				t.Errorf("sanitizeHostname(%q) is %d characters long", tt.hostname, len(got))
+			}
+		})
+	}
+}
+
+func TestGenerateHostname(t *testing.T) {
+	lease := v1alpha1.Lease{
+		Spec: v1alpha1.LeaseSpec{
+			Ip:  "10.0.0.15",
+			Mac: "AA:bb:cc:dd:ee:ff",
+		},
+	}
+
+	tests := []struct {
+		name     string
+		template string
+		want     string
+	}{
+		{"no template", "", ""},
+		{"pool and ip", "{{pool}}-{{ip-dashed}}", "vlan-10-10-0-0-15"},
+		{"mac", "host-{{mac}}", "host-aabbccddeeff"},
+		{"mac dashed", "{{mac-dashed}}", "aa-bb-cc-dd-ee-ff"},
+		{"unknown placeholder is sanitized", "{{unknown}}", "unknown"},
+		{"invalid characters", "Host_{{mac}}", "host-aabbccddeeff"},
+		{"overlong", strings.Repeat("x", 60) + "-{{mac}}", strings.Repeat("x", 60) + "-aa"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pool := v1alpha1.Pool{
+				ObjectMeta: metav1.ObjectMeta{Name: "vlan-10"},
+			}
+			pool.Spec.Hostname.Template = tt.template
+
+			got := generateHostname(pool, lease)
+			if got != tt.want {
+				t.Errorf("generateHostname(%q) = %q, want %q", tt.template, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/CRASH-Tech/dhcp-operator/cmd/kubernetes/api/v1alpha1"
+	"github.com/insomniacslk/dhcp/dhcpv4"
+	log "github.com/sirupsen/logrus"
+)
+
+const vendorClassHTTPClient = "HTTPClient"
+
+// isHTTPBootClient reports whether the client is a UEFI HTTP Boot client,
+// which identifies as "HTTPClient:Arch:..." in the vendor class.
+func isHTTPBootClient(msg *dhcpv4.DHCPv4) bool {
+	return strings.HasPrefix(msg.ClassIdentifier(), vendorClassHTTPClient)
+}
+
+// setBootFile sets the bootfile for the client. HTTP Boot clients get the
+// URL-form filename and the vendor class echoed back, as the firmware
+// ignores offers without it. PXE clients keep the TFTP filename.
+func setBootFile(reply *dhcpv4.DHCPv4, msg *dhcpv4.DHCPv4, lease v1alpha1.Lease, pool v1alpha1.Pool) {
+	if !isHTTPBootClient(msg) {
+		reply.UpdateOption(dhcpv4.OptBootFileName(signBootFile(pool.Spec.Filename, lease.Spec.Mac)))
+
+		return
+	}
+
+	filename := httpBootFile(pool)
+	if filename == "" {
+		log.Warnf("No HTTP boot filename in pool %s for %s", pool.Name, lease.Spec.Mac)
+
+		return
+	}
+
+	reply.UpdateOption(dhcpv4.OptClassIdentifier(vendorClassHTTPClient))
+	reply.UpdateOption(dhcpv4.OptBootFileName(signBootFile(filename, lease.Spec.Mac)))
+}
+
+// httpBootFile prefers spec.httpFilename and falls back to spec.filename when
+// it is already a URL.
+func httpBootFile(pool v1alpha1.Pool) string {
+	if pool.Spec.HttpFilename != "" {
+		return pool.Spec.HttpFilename
+	}
+
+	if isURLFilename(pool.Spec.Filename) {
+		return pool.Spec.Filename
+	}
+
+	return ""
+}
+
+func isURLFilename(filename string) bool {
+	u, err := url.Parse(filename)
+
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+)
+
+type requestConfigKey struct{}
+
+var (
+	httpRequests = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "pxe_http_requests_total",
+			Help: "The number of PXE server HTTP requests",
+		},
+		[]string{
+			"route",
+			"config",
+			"code",
+		},
+	)
+
+	httpRequestDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "pxe_http_request_duration_seconds",
+			Help:    "The duration of PXE server HTTP requests",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{
+			"route",
+			"config",
+		},
+	)
+)
+
+// accessLogWriter records the status and size of a response.
+type accessLogWriter struct {
+	http.ResponseWriter
+	status int
+	bytes  int64
+}
+
+func (w *accessLogWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *accessLogWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += int64(n)
+
+	return n, err
+}
+
+// setRequestConfig records the name of the served PXE config for the access
+// log and the metrics.
+func setRequestConfig(r *http.Request, name string) {
+	if configName, ok := r.Context().Value(requestConfigKey{}).(*string); ok {
+		*configName = name
+	}
+}
+
+// route wraps a handler with the allowed methods, the access log and the
+// request metrics.
+func route(name string, handler http.Handler, methods ...string) http.Handler {
+	if len(methods) == 0 {
+		methods = []string{http.MethodGet, http.MethodHead}
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
+		var configName string
+		r = r.WithContext(context.WithValue(r.Context(), requestConfigKey{}, &configName))
+		writer := &accessLogWriter{ResponseWriter: w}
+
+		if isMethodAllowed(r.Method, methods) {
+			handler.ServeHTTP(writer, r)
+		} else {
+			for _, method := range methods {
+				writer.Header().Add("Allow", method)
+			}
+			writer.WriteHeader(http.StatusMethodNotAllowed)
+			writer.Write([]byte("Method not allowed!"))
+		}
+
+		if writer.status == 0 {
+			writer.status = http.StatusOK
+		}
+		duration := time.Since(start)
+
+		httpRequests.WithLabelValues(name, configName, strconv.Itoa(writer.status)).Inc()
+		httpRequestDuration.WithLabelValues(name, configName).Observe(duration.Seconds())
+
+		ip := remoteIP(r)
+		var mac string
+		lease, found, err := findClientLease(r.URL.Query().Get("mac"), "", ip)
+		if err == nil && found {
+			mac = lease.Spec.Mac
+		}
+
+		log.WithFields(log.Fields{
+			"method":   r.Method,
+			"path":     r.URL.Path,
+			"status":   writer.status,
+			"bytes":    writer.bytes,
+			"duration": duration.String(),
+			"ip":       ip.String(),
+			"mac":      mac,
+			"config":   configName,
+		}).Info("HTTP request")
+	})
+}
+
+func isMethodAllowed(method string, methods []string) bool {
+	for _, m := range methods {
+		if m == method {
+			return true
+		}
+	}
+
+	return false
+}
@@ -2,11 +2,11 @@ package main
 
 import (
 	"context"
-	"os"
-	"time"
+	"fmt"
 
 	log "github.com/sirupsen/logrus"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/leaderelection"
 	"k8s.io/client-go/tools/leaderelection/resourcelock"
 )
@@ -24,48 +24,41 @@ func getNewLock(lockname, podname, namespace string) *resourcelock.LeaseLock {
 	}
 }
 
+// setLeaderLabel marks the pod, so a service can select the leader. It is
+// optional and never fatal, the operator works without it on restricted RBAC.
 func setLeaderLabel(isLeader bool) {
-	pod, err := config.KubernetesClient.CoreV1().Pods(namespace).Get(context.TODO(), hostname, metav1.GetOptions{})
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	existingLabels := pod.ObjectMeta.Labels
-	if isLeader {
-		existingLabels["leader"] = "true"
-	} else {
-		existingLabels["leader"] = "false"
+	if !config.LeaderElection.LeaderLabel {
+		return
 	}
 
-	pod.ObjectMeta.Labels = existingLabels
-
-	updatedPod, err := config.KubernetesClient.CoreV1().Pods(namespace).Update(context.TODO(), pod, metav1.UpdateOptions{})
+	patch := fmt.Sprintf(`{"metadata":{"labels":{"leader":"%t"}}}`, isLeader)
+	updatedPod, err := config.KubernetesClient.CoreV1().Pods(namespace).Patch(context.TODO(), hostname, types.MergePatchType, []byte(patch), metav1.PatchOptions{})
 	if err != nil {
-		log.Fatal(err)
+		log.Warnf("Cannot update pod leader label: %s", err)
+
+		return
 	}
 
-	log.Infof("Updated pod label: %s\n", updatedPod.Labels["leader"])
+	log.Infof("Updated pod label: %s", updatedPod.Labels["leader"])
 }
 
 func runLeaderElection(lock *resourcelock.LeaseLock, ctx context.Context, id string) {
 	leaderelection.RunOrDie(ctx, leaderelection.LeaderElectionConfig{
 		Lock:            lock,
 		ReleaseOnCancel: true,
-		LeaseDuration:   15 * time.Second,
-		RenewDeadline:   10 * time.Second,
-		RetryPeriod:     2 * time.Second,
+		LeaseDuration:   config.LeaderElection.LeaseDuration,
+		RenewDeadline:   config.LeaderElection.RenewDeadline,
+		RetryPeriod:     config.LeaderElection.RetryPeriod,
 		Callbacks: leaderelection.LeaderCallbacks{
 			OnStartedLeading: func(c context.Context) {
-				mutex.Unlock()
 				setLeaderLabel(true)
-				worker()
+				readiness.leader.Store(true)
+				worker(c)
 			},
 			OnStoppedLeading: func() {
-				log.Warn("We are no longer the leader, terminating...")
-				mutex.Lock()
+				log.Warn("We are no longer the leader, stopping worker...")
+				readiness.leader.Store(false)
 				setLeaderLabel(false)
-
-				os.Exit(0)
 			},
 			OnNewLeader: func(current_id string) {
 				if current_id == id {
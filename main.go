@@ -2,39 +2,50 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"io/ioutil"
 	"net"
 	"os"
+	"os/signal"
 	"sort"
 	"strconv"
 	"strings"
 	"sync"
+	"syscall"
 	"time"
 
 	"github.com/CRASH-Tech/dhcp-operator/cmd/common"
 	"github.com/CRASH-Tech/dhcp-operator/cmd/kubernetes"
-	"github.com/CRASH-Tech/dhcp-operator/cmd/kubernetes/api"
 	"github.com/CRASH-Tech/dhcp-operator/cmd/kubernetes/api/v1alpha1"
+	"github.com/CRASH-Tech/dhcp-operator/cmd/kubernetes/generated/clientset/versioned"
 	"github.com/insomniacslk/dhcp/dhcpv4"
 	"github.com/insomniacslk/dhcp/dhcpv4/server4"
 	"github.com/prometheus/client_golang/prometheus"
 	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/dynamic"
 	k8s "k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	scopeCluster    = "cluster"
+	scopeNamespaced = "namespaced"
 )
 
 var (
-	version       = "0.0.7"
-	config        common.Config
-	kClient       *kubernetes.Client
-	namespace     string
-	hostname      string
-	leaseLockName = "dhcp-operator"
+	version   = "0.0.7"
+	config    common.Config
+	kClient   *kubernetes.Client
+	namespace string
+	hostname  string
 
-	mutex sync.Mutex
+	mutex       sync.Mutex
+	workerMutex sync.Mutex
 
 	leaseExpiration = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
@@ -50,7 +61,9 @@ var (
 	)
 )
 
-func init() {
+// setup reads the config and connects to the cluster. It runs from main
+// rather than init, so the package can be tested without a cluster.
+func setup() {
 	var configPath string
 	flag.StringVar(&configPath, "c", "config.yaml", "config file path. Default: config.yaml")
 	c, err := readConfig(configPath)
@@ -96,8 +109,9 @@ func init() {
 
 	config.DynamicClient = dynamic.NewForConfigOrDie(restConfig)
 	config.KubernetesClient = k8s.NewForConfigOrDie(restConfig)
+	config.DhcpClient = versioned.NewForConfigOrDie(restConfig)
 
-	prometheus.MustRegister(leaseExpiration)
+	prometheus.MustRegister(leaseExpiration, tftpTransfers, tftpSentBytes, httpRequests, httpRequestDuration)
 
 	ns, err := ioutil.ReadFile("/var/run/secrets/kubernetes.io/serviceaccount/namespace")
 	if err != nil {
@@ -106,35 +120,113 @@ func init() {
 
 	namespace = string(ns)
 	hostname = os.Getenv("HOSTNAME")
+
+	if config.Scope == scopeNamespaced && len(config.WatchNamespaces) == 0 {
+		config.WatchNamespaces = []string{namespace}
+	}
+	if config.Scope != scopeNamespaced {
+		config.WatchNamespaces = nil
+	}
 }
 
 func main() {
-	ctx, cancel := context.WithCancel(context.Background())
+	setup()
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer cancel()
 
-	kClient = kubernetes.NewClient(ctx, *config.DynamicClient, *config.KubernetesClient)
+	kClient = kubernetes.NewClient(ctx, *config.DynamicClient, *config.KubernetesClient, config.DhcpClient, config.WatchNamespaces)
+	kClient.StartCache(ctx, 10*time.Minute)
+	startEventRecorder(ctx)
+	listenWebhook(ctx)
+	listenOps(ctx)
+
+	if isActiveActive() {
+		log.Info("Running in active-active mode")
+		readiness.leader.Store(true)
+
+		membershipDone := make(chan struct{})
+		go func() {
+			defer close(membershipDone)
+			runMembership(ctx)
+		}()
+
+		worker(ctx)
+		<-membershipDone
+
+		return
+	}
+
+	if !config.LeaderElection.Enabled {
+		log.Info("Leader election is disabled")
+		readiness.leader.Store(true)
+		worker(ctx)
+
+		return
+	}
 
-	mutex.Lock()
 	setLeaderLabel(false)
 
-	lock := getNewLock(leaseLockName, hostname, namespace)
-	runLeaderElection(lock, ctx, hostname)
+	lock := getNewLock(config.LeaderElection.LeaseName, hostname, namespace)
+	for ctx.Err() == nil {
+		runLeaderElection(lock, ctx, hostname)
+	}
+
+	// The worker runs in its own goroutine in leader mode, wait for it to
+	// shut down the servers before exiting.
+	workerMutex.Lock()
+	defer workerMutex.Unlock()
 }
 
-func worker() {
+// worker serves DHCP and PXE until ctx is cancelled and returns once
+// everything it started has stopped.
+func worker(ctx context.Context) {
+	workerMutex.Lock()
+	defer workerMutex.Unlock()
+
 	log.Infof("Starting dhcp-operator %s", version)
-	ticker := time.NewTicker(10 * time.Second)
+
+	if !kClient.WaitForCacheSync(ctx) {
+		log.Warn("Cache is not synced, using API directly")
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
 	go func() {
+		defer wg.Done()
+
+		ticker := time.NewTicker(10 * time.Second)
+		defer ticker.Stop()
+
 		for {
 			select {
+			case <-ctx.Done():
+				return
 			case <-ticker.C:
 				metrics()
-				leaseCleaner()
+				if isPrimaryReplica() {
+					finalizerSync()
+					leaseCleaner()
+					poolReconcile()
+					poolStatusSync()
+
+					err := dnsSync()
+					if err != nil {
+						log.Error(err)
+					}
+				}
 			}
 		}
 	}()
 
-	listenPXE()
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		runArtifactSync(ctx)
+	}()
+
+	pxeServers := listenPXE(ctx)
+	tftpServer := listenTFTP()
 
 	laddr := &net.UDPAddr{
 		IP:   net.ParseIP("0.0.0.0"),
@@ -145,12 +237,44 @@ func worker() {
 	if err != nil {
 		log.Fatal(err)
 	}
+	readiness.dhcpBound.Store(true)
 
-	server.Serve()
+	go func() {
+		<-ctx.Done()
+		server.Close()
+	}()
+
+	err = server.Serve()
+	readiness.dhcpBound.Store(false)
+	if err != nil && ctx.Err() == nil {
+		log.Error(err)
+	}
+
+	log.Info("Stopping dhcp-operator worker...")
+
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer shutdownCancel()
+
+	for _, pxeServer := range pxeServers {
+		err = pxeServer.Shutdown(shutdownCtx)
+		if err != nil {
+			log.Error(err)
+		}
+	}
+
+	if tftpServer != nil {
+		err = tftpServer.Shutdown(shutdownCtx)
+		if err != nil {
+			log.Error(err)
+		}
+	}
+
+	wg.Wait()
+	log.Info("Worker stopped")
 }
 
 func metrics() {
-	leases, err := kClient.V1alpha1().Lease().GetAll()
+	leases, err := kClient.V1alpha1().Lease().GetAllCached()
 	if err != nil {
 		log.Error(err)
 
@@ -175,6 +299,12 @@ func metrics() {
 }
 
 func handler(conn net.PacketConn, peer net.Addr, msg *dhcpv4.DHCPv4) {
+	if !isResponsible(msg.ClientHWAddr) {
+		log.Debugf("Skip message from %s, served by another replica", msg.ClientHWAddr)
+
+		return
+	}
+
 	mutex.Lock()
 	defer mutex.Unlock()
 
@@ -191,6 +321,9 @@ func handler(conn net.PacketConn, peer net.Addr, msg *dhcpv4.DHCPv4) {
 	case dhcpv4.MessageTypeRelease:
 		release(conn, peer, *msg)
 
+	case dhcpv4.MessageTypeDecline:
+		decline(conn, peer, *msg)
+
 	default:
 		log.Info(msg.MessageType())
 	}
@@ -200,6 +333,11 @@ func discover(conn net.PacketConn, peer net.Addr, msg dhcpv4.DHCPv4) {
 	log.Debug("Received DISCOVER message:\n", msg.Summary())
 
 	lease, found, err := getLease(msg)
+	if kubernetes.IsUnavailable(err) {
+		serveDegraded(conn, peer, msg, dhcpv4.MessageTypeOffer)
+
+		return
+	}
 	if err != nil {
 		log.Error(err)
 
@@ -209,6 +347,16 @@ func discover(conn net.PacketConn, peer net.Addr, msg dhcpv4.DHCPv4) {
 	if found {
 		log.Debugf("Found existing lease IP: %s MAC: %s", lease.Spec.Ip, lease.Spec.Mac)
 		reply, err := makeReply(msg, lease, dhcpv4.MessageTypeOffer)
+		if kubernetes.IsUnavailable(err) {
+			serveDegraded(conn, peer, msg, dhcpv4.MessageTypeOffer)
+
+			return
+		}
+		if errors.Is(err, errPoolDraining) || errors.Is(err, errLeaseOutOfRange) {
+			moveLease(conn, peer, msg, lease, err)
+
+			return
+		}
 		if err != nil {
 			log.Error(err)
 
@@ -256,8 +404,13 @@ func discover(conn net.PacketConn, peer net.Addr, msg dhcpv4.DHCPv4) {
 			return
 		}
 
-		if len(ips) > 0 {
-			lease, err := newLease(ips[0], pool, msg)
+		for _, ip := range ips {
+			lease, err := newLease(ip, pool, msg)
+			if kubernetes.IsConflict(err) {
+				log.Debugf("IP %s was taken concurrently, try next one", ip)
+
+				continue
+			}
 			if err != nil {
 				log.Error(err)
 
@@ -282,12 +435,20 @@ func discover(conn net.PacketConn, peer net.Addr, msg dhcpv4.DHCPv4) {
 	}
 
 	log.Error("Cannot make reply, no avialable ips:\n", msg.Summary())
+	for _, pool := range pools {
+		poolEvent(pool, corev1.EventTypeWarning, eventPoolExhausted, "No available addresses for %s", msg.ClientHWAddr)
+	}
 }
 
 func request(conn net.PacketConn, peer net.Addr, msg dhcpv4.DHCPv4) {
 	log.Debug("Received REQUEST message:\n", msg.Summary())
 
 	lease, found, err := getLease(msg)
+	if kubernetes.IsUnavailable(err) {
+		serveDegraded(conn, peer, msg, dhcpv4.MessageTypeAck)
+
+		return
+	}
 	if err != nil {
 		log.Error(err)
 
@@ -296,6 +457,17 @@ func request(conn net.PacketConn, peer net.Addr, msg dhcpv4.DHCPv4) {
 
 	if found {
 		reply, err := makeReply(msg, lease, dhcpv4.MessageTypeAck)
+		if kubernetes.IsUnavailable(err) {
+			serveDegraded(conn, peer, msg, dhcpv4.MessageTypeAck)
+
+			return
+		}
+		if errors.Is(err, errPoolDraining) || errors.Is(err, errLeaseOutOfRange) {
+			log.Infof("NAK renewal of lease %s: %s", lease.Name, err)
+			nak(conn, peer, msg)
+
+			return
+		}
 		if err != nil {
 			log.Error(err)
 
@@ -308,6 +480,7 @@ func request(conn net.PacketConn, peer net.Addr, msg dhcpv4.DHCPv4) {
 
 			return
 		}
+		leaseEvent(lease, corev1.EventTypeNormal, eventLeaseRenewed, "Lease %s renewed by %s", lease.Spec.Ip, lease.Spec.Mac)
 
 		return
 	} else {
@@ -335,6 +508,7 @@ func release(conn net.PacketConn, peer net.Addr, msg dhcpv4.DHCPv4) {
 
 			return
 		}
+		leaseEvent(lease, corev1.EventTypeNormal, eventLeaseReleased, "Lease %s released by %s", lease.Spec.Ip, lease.Spec.Mac)
 	} else {
 		log.Error("Cannot release lease, lease not found:\n", msg.Summary())
 
@@ -342,33 +516,83 @@ func release(conn net.PacketConn, peer net.Addr, msg dhcpv4.DHCPv4) {
 	}
 }
 
+// decline handles a client that found its offered address already in use,
+// the lease is dropped so the client gets a new one on the next DISCOVER.
+func decline(conn net.PacketConn, peer net.Addr, msg dhcpv4.DHCPv4) {
+	log.Debug("Received DECLINE message:\n", msg.Summary())
+
+	lease, found, err := getLease(msg)
+	if err != nil {
+		log.Error(err)
+
+		return
+	}
+
+	if !found {
+		log.Error("Cannot decline lease, lease not found:\n", msg.Summary())
+
+		return
+	}
+
+	log.Warnf("Lease %s declined by %s, address is in use", lease.Spec.Ip, lease.Spec.Mac)
+	leaseEvent(lease, corev1.EventTypeWarning, eventLeaseDeclined, "Lease %s declined by %s, address is in use", lease.Spec.Ip, lease.Spec.Mac)
+
+	err = kClient.V1alpha1().Lease().Delete(lease)
+	if err != nil {
+		log.Error(err)
+
+		return
+	}
+}
+
 func makeReply(msg dhcpv4.DHCPv4, lease v1alpha1.Lease, msgType dhcpv4.MessageType) (*dhcpv4.DHCPv4, error) {
 	reply, err := dhcpv4.NewReplyFromRequest(&msg)
 	if err != nil {
 		return nil, err
 	}
 
-	pool, err := kClient.V1alpha1().Pool().Get(lease.Spec.Pool)
+	pool, err := kClient.V1alpha1().Pool().Get(lease.Namespace, lease.Spec.Pool)
 	if err != nil {
 		return reply, err
 	}
 
+	// A draining pool keeps its clients until their leases end: renewals
+	// are answered with the remaining time and the lease is not extended.
+	if isPoolDraining(pool) {
+		remaining := leaseRemaining(lease)
+		if remaining <= 0 {
+			return reply, errPoolDraining
+		}
+
+		return buildReply(&msg, reply, lease, pool, msgType, remaining)
+	}
+
+	if !isLeaseInPool(lease, pool) {
+		return reply, errLeaseOutOfRange
+	}
+
 	duration, err := time.ParseDuration(pool.Spec.Lease)
 	if err != nil {
 		return reply, err
 	}
 
-	lease.Spec.Static = pool.Spec.Static
-	lease, err = kClient.V1alpha1().Lease().Patch(lease)
-	if err != nil {
-		return reply, err
+	if lease.Spec.Static != pool.Spec.Static {
+		lease, err = setLeaseStatic(lease, pool.Spec.Static)
+		if err != nil {
+			return reply, err
+		}
 	}
 
-	lease, err = kClient.V1alpha1().Lease().Renew(lease, string(msg.Options.Get(dhcpv4.OptionHostName)), duration)
+	leaseHostname := resolveHostname(pool, lease, string(msg.Options.Get(dhcpv4.OptionHostName)))
+	lease, err = kClient.V1alpha1().Lease().Renew(lease, leaseHostname, duration)
 	if err != nil {
 		return reply, err
 	}
 
+	return buildReply(&msg, reply, lease, pool, msgType, duration)
+}
+
+func buildReply(msg *dhcpv4.DHCPv4, reply *dhcpv4.DHCPv4, lease v1alpha1.Lease, pool v1alpha1.Pool, msgType dhcpv4.MessageType, duration time.Duration) (*dhcpv4.DHCPv4, error) {
 	poolMask, err := pool.GetMask()
 	if err != nil {
 		return reply, err
@@ -376,7 +600,7 @@ func makeReply(msg dhcpv4.DHCPv4, lease v1alpha1.Lease, msgType dhcpv4.MessageTy
 
 	reply.UpdateOption(dhcpv4.OptMessageType(msgType))
 	reply.YourIPAddr = net.ParseIP(lease.Spec.Ip)
-	reply.UpdateOption(dhcpv4.OptServerIdentifier(msg.GatewayIPAddr)) //////////////////////////////////////////////////////////////////////////////////TODO: LOL
+	reply.UpdateOption(dhcpv4.OptServerIdentifier(reply.GatewayIPAddr)) //////////////////////////////////////////////////////////////////////////////////TODO: LOL
 	reply.UpdateOption(dhcpv4.OptRequestedIPAddress(net.ParseIP(lease.Spec.Ip)))
 	reply.UpdateOption(dhcpv4.OptSubnetMask(poolMask))
 	reply.UpdateOption(dhcpv4.OptRouter(net.ParseIP(pool.Spec.Routers)))
@@ -384,11 +608,34 @@ func makeReply(msg dhcpv4.DHCPv4, lease v1alpha1.Lease, msgType dhcpv4.MessageTy
 	reply.UpdateOption(dhcpv4.OptNTPServers(pool.GetNTP()...))
 	reply.UpdateOption(dhcpv4.OptIPAddressLeaseTime(duration))
 	reply.UpdateOption(dhcpv4.OptHostName(lease.Status.Hostname))
-	reply.UpdateOption(dhcpv4.OptBootFileName(pool.Spec.Filename))
+	setBootFile(reply, msg, lease, pool)
+	if config.Tftp.NextServer != "" {
+		reply.ServerIPAddr = net.ParseIP(config.Tftp.NextServer)
+		reply.UpdateOption(dhcpv4.OptTFTPServerName(config.Tftp.NextServer))
+	}
 
 	return reply, nil
 }
 
+func nak(conn net.PacketConn, peer net.Addr, msg dhcpv4.DHCPv4) {
+	reply, err := dhcpv4.NewReplyFromRequest(&msg)
+	if err != nil {
+		log.Error(err)
+
+		return
+	}
+
+	reply.UpdateOption(dhcpv4.OptMessageType(dhcpv4.MessageTypeNak))
+	reply.UpdateOption(dhcpv4.OptServerIdentifier(reply.GatewayIPAddr))
+
+	err = sendReply(conn, peer, reply)
+	if err != nil {
+		log.Error(err)
+
+		return
+	}
+}
+
 func sendReply(conn net.PacketConn, peer net.Addr, msg *dhcpv4.DHCPv4) error {
 	ipPort := strings.Split(peer.String(), ":")
 	destIP := net.ParseIP(ipPort[0])
@@ -419,10 +666,18 @@ func leaseCleaner() {
 	}
 
 	for _, lease := range leases {
+		if lease.DeletionTimestamp != nil {
+			continue
+		}
+
 		if lease.Status.Starts == "" {
-			lease.Status.Starts = strconv.FormatInt(time.Now().Unix(), 10)
+			starts := strconv.FormatInt(time.Now().Unix(), 10)
 
-			lease, err = kClient.V1alpha1().Lease().UpdateStatus(lease)
+			lease, err = kClient.V1alpha1().Lease().UpdateStatus(lease, func(l *v1alpha1.Lease) {
+				if l.Status.Starts == "" {
+					l.Status.Starts = starts
+				}
+			})
 			if err != nil {
 				log.Error(err)
 
@@ -431,7 +686,7 @@ func leaseCleaner() {
 		}
 
 		if lease.Status.Ends == "" {
-			pool, err := kClient.V1alpha1().Pool().Get(lease.Spec.Pool)
+			pool, err := kClient.V1alpha1().Pool().Get(lease.Namespace, lease.Spec.Pool)
 			if err != nil {
 				log.Error(err)
 
@@ -445,9 +700,11 @@ func leaseCleaner() {
 				continue
 			}
 
-			lease.Status.Ends = strconv.FormatInt(time.Now().Add(duration).Unix(), 10)
-
-			lease, err = kClient.V1alpha1().Lease().UpdateStatus(lease)
+			lease, err = kClient.V1alpha1().Lease().UpdateStatus(lease, func(l *v1alpha1.Lease) {
+				if l.Status.Ends == "" {
+					l.Status.SetEnds(time.Now().Add(duration))
+				}
+			})
 			if err != nil {
 				log.Error(err)
 
@@ -456,9 +713,9 @@ func leaseCleaner() {
 		}
 
 		if lease.Spec.Static {
-			log.Debugf("Skip delete static lease: %s", lease)
+			log.Debugf("Skip delete static lease: %s", lease.Name)
 
-			pool, err := kClient.V1alpha1().Pool().Get(lease.Spec.Pool)
+			pool, err := kClient.V1alpha1().Pool().Get(lease.Namespace, lease.Spec.Pool)
 			if err != nil {
 				log.Error(err)
 
@@ -472,9 +729,9 @@ func leaseCleaner() {
 				continue
 			}
 
-			lease.Status.Ends = strconv.FormatInt(time.Now().Add(duration).Unix(), 10)
-
-			lease, err = kClient.V1alpha1().Lease().UpdateStatus(lease)
+			lease, err = kClient.V1alpha1().Lease().UpdateStatus(lease, func(l *v1alpha1.Lease) {
+				l.Status.SetEnds(time.Now().Add(duration))
+			})
 			if err != nil {
 				log.Error(err)
 
@@ -490,14 +747,17 @@ func leaseCleaner() {
 
 			continue
 		}
-		ends := time.Unix(e, 0).Add(time.Duration(time.Minute * 5))
+		ends := time.Unix(e, 0).Add(leaseCleanerGrace)
 
 		if ends.Before(time.Now()) {
-			log.Warnf("Delete expired lease: %s", lease)
+			log.Warnf("Delete expired lease: %s", lease.Name)
 			err := kClient.V1alpha1().Lease().Delete(lease)
 			if err != nil {
 				log.Error(err)
+
+				continue
 			}
+			leaseEvent(lease, corev1.EventTypeNormal, eventLeaseExpired, "Lease %s of %s expired", lease.Spec.Ip, lease.Spec.Mac)
 		}
 	}
 }
@@ -509,6 +769,10 @@ func getLease(msg dhcpv4.DHCPv4) (v1alpha1.Lease, bool, error) {
 	}
 
 	for _, lease := range leases {
+		if lease.DeletionTimestamp != nil {
+			continue
+		}
+
 		if strings.EqualFold(lease.Spec.Mac, msg.ClientHWAddr.String()) {
 			return lease, true, nil
 		}
@@ -525,27 +789,77 @@ func newLease(ip net.IP, pool v1alpha1.Pool, msg dhcpv4.DHCPv4) (v1alpha1.Lease,
 		return v1alpha1.Lease{}, err
 	}
 
-	ownerReference := api.CustomResourceOwnerReference{
-		ApiVersion:         pool.APIVersion,
-		Kind:               pool.Kind,
-		Name:               pool.Metadata.Name,
-		Uid:                pool.Metadata.Uid,
-		BlockOwnerDeletion: true,
+	blockOwnerDeletion := true
+	ownerReference := metav1.OwnerReference{
+		APIVersion:         v1alpha1.SchemeGroupVersion.String(),
+		Kind:               "Pool",
+		Name:               pool.Name,
+		UID:                pool.UID,
+		BlockOwnerDeletion: &blockOwnerDeletion,
 	}
 
 	lease := v1alpha1.Lease{}
-	lease.Metadata.Name = ip.String()
-	lease.Metadata.OwnerReferences = []api.CustomResourceOwnerReference{ownerReference}
+	lease.Name = ip.String()
+	lease.Namespace = pool.Namespace
+	lease.OwnerReferences = []metav1.OwnerReference{ownerReference}
+	lease.Finalizers = []string{leaseFinalizer}
 	lease.Spec.Ip = ip.String()
 	lease.Spec.Mac = strings.ToUpper(msg.ClientHWAddr.String())
-	lease.Spec.Pool = pool.Metadata.Name
+	lease.Spec.Pool = pool.Name
 	lease.Spec.Static = pool.Spec.Static
-	lease.Status.Ends = strconv.FormatInt(time.Now().Add(duration).Unix(), 10)
+	lease.Status.SetEnds(time.Now().Add(duration))
+
+	created, err := kClient.V1alpha1().Lease().Create(lease)
+	if kubernetes.IsConflict(err) {
+		// The lease name is the IP, so a conflict means the IP was taken
+		// concurrently, unless it was taken by the same client.
+		existing, getErr := kClient.V1alpha1().Lease().Get(lease.Namespace, lease.Name)
+		if getErr == nil && strings.EqualFold(existing.Spec.Mac, lease.Spec.Mac) {
+			return existing, nil
+		}
+		if getErr == nil {
+			leaseEvent(existing, corev1.EventTypeWarning, eventConflictDetected, "Address %s was requested for %s but is held by %s", lease.Spec.Ip, lease.Spec.Mac, existing.Spec.Mac)
+		}
 
-	lease, err = kClient.V1alpha1().Lease().Create(lease)
+		return v1alpha1.Lease{}, err
+	}
 	if err != nil {
-		return lease, err
+		return v1alpha1.Lease{}, err
 	}
+	leaseEvent(created, corev1.EventTypeNormal, eventLeaseGranted, "Lease %s granted to %s", created.Spec.Ip, created.Spec.Mac)
+
+	return created, nil
+}
+
+func setLeaseStatic(lease v1alpha1.Lease, static bool) (v1alpha1.Lease, error) {
+	return patchLeaseSpec(lease, func(spec *v1alpha1.LeaseSpec) {
+		spec.Static = static
+	})
+}
+
+// patchLeaseSpec applies mutate and patches the lease, retrying on conflicts
+// with a fresh copy.
+func patchLeaseSpec(lease v1alpha1.Lease, mutate func(*v1alpha1.LeaseSpec)) (v1alpha1.Lease, error) {
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		mutate(&lease.Spec)
+
+		patched, err := kClient.V1alpha1().Lease().Patch(lease)
+		if kubernetes.IsConflict(err) {
+			fresh, getErr := kClient.V1alpha1().Lease().Get(lease.Namespace, lease.Name)
+			if getErr != nil {
+				return getErr
+			}
+
+			lease = fresh
+		}
+		if err != nil {
+			return err
+		}
+
+		lease = patched
+
+		return nil
+	})
 
-	return lease, nil
+	return lease, err
 }
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/pprof"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	log "github.com/sirupsen/logrus"
+)
+
+// readiness tracks the state reported by /readyz, the cache state is read
+// from the client directly.
+var readiness struct {
+	leader    atomic.Bool
+	dhcpBound atomic.Bool
+}
+
+// listenOps serves metrics, health checks and optionally pprof on a separate
+// address, so ops traffic can be firewalled apart from boot traffic. It runs
+// on every replica, independently of the leader election, until ctx is
+// cancelled.
+func listenOps(ctx context.Context) {
+	if config.Ops.Addr == "" {
+		return
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	mux.HandleFunc("/healthz", healthzHandler)
+	mux.HandleFunc("/readyz", readyzHandler)
+	if config.Ops.Pprof {
+		mux.HandleFunc("/debug/pprof/", pprof.Index)
+		mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+		mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+		mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+		mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	}
+
+	server := &http.Server{
+		Addr:              config.Ops.Addr,
+		Handler:           mux,
+		ReadHeaderTimeout: config.Http.ReadHeaderTimeout,
+	}
+
+	go func() {
+		<-ctx.Done()
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		server.Shutdown(shutdownCtx)
+	}()
+
+	go func() {
+		log.Infof("Starting ops server on %s", config.Ops.Addr)
+		err := server.ListenAndServe()
+		if err != nil && err != http.ErrServerClosed {
+			log.Fatal(err)
+		}
+	}()
+}
+
+func healthzHandler(w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte("ok"))
+}
+
+// readyzHandler reports ready only when this replica leads, the cache is
+// synced and the DHCP socket is bound.
+func readyzHandler(w http.ResponseWriter, r *http.Request) {
+	checks := []struct {
+		name string
+		ok   bool
+	}{
+		{"leader", readiness.leader.Load()},
+		{"cache", kClient.CacheSynced()},
+		{"dhcp", readiness.dhcpBound.Load()},
+	}
+
+	for _, check := range checks {
+		if !check.ok {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(check.name + " is not ready"))
+
+			return
+		}
+	}
+
+	w.Write([]byte("ok"))
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/CRASH-Tech/dhcp-operator/cmd/kubernetes/api/v1alpha1"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// poolStatusSync recalculates the address usage and conditions of every
+// pool and writes the status back when it changed.
+func poolStatusSync() {
+	log.Debug("Start pool status sync...")
+
+	pools, err := kClient.V1alpha1().Pool().GetAllCached()
+	if err != nil {
+		log.Error(err)
+
+		return
+	}
+
+	leases, err := kClient.V1alpha1().Lease().GetAllCached()
+	if err != nil {
+		log.Error(err)
+
+		return
+	}
+
+	for _, pool := range pools {
+		status := getPoolStatus(pool, pools, leases)
+		if reflect.DeepEqual(status, pool.Status) {
+			continue
+		}
+
+		pool.Status = status
+
+		_, err := kClient.V1alpha1().Pool().UpdateStatus(pool)
+		if err != nil {
+			log.Error(err)
+
+			continue
+		}
+	}
+}
+
+func getPoolStatus(pool v1alpha1.Pool, pools []v1alpha1.Pool, leases []v1alpha1.Lease) v1alpha1.PoolStatus {
+	status := *pool.Status.DeepCopy()
+	status.ObservedGeneration = pool.Generation
+	status.Total = poolSize(pool)
+	status.Used = 0
+	status.Reserved = 0
+	status.Quarantined = 0
+	status.OutOfRange = nil
+
+	for _, lease := range leases {
+		if lease.Namespace != pool.Namespace || lease.Spec.Pool != pool.Name {
+			continue
+		}
+
+		// Leases outside of the range do not take pool addresses.
+		if !isLeaseInPool(lease, pool) {
+			status.OutOfRange = append(status.OutOfRange, lease.Name)
+
+			continue
+		}
+
+		switch {
+		case lease.Spec.Static:
+			status.Reserved++
+		case lease.Status.Ends != "" && !isLeaseActive(lease):
+			// Expired leases keep their address until the cleaner deletes them.
+			status.Quarantined++
+		default:
+			status.Used++
+		}
+
+		if status.LastAllocation == nil || status.LastAllocation.Before(&lease.CreationTimestamp) {
+			created := lease.CreationTimestamp
+			status.LastAllocation = &created
+		}
+	}
+
+	sort.Strings(status.OutOfRange)
+
+	status.Free = status.Total - status.Used - status.Reserved - status.Quarantined
+	if status.Free < 0 {
+		status.Free = 0
+	}
+
+	errs := validatePool(pool, nil)
+	if len(errs) > 0 {
+		setPoolCondition(&status, pool, v1alpha1.PoolConditionValid, false, "InvalidSpec", strings.Join(errs, "; "))
+	} else {
+		setPoolCondition(&status, pool, v1alpha1.PoolConditionValid, true, "Valid", "Pool spec is valid")
+	}
+
+	if status.Free == 0 {
+		setPoolCondition(&status, pool, v1alpha1.PoolConditionExhausted, true, "NoFreeAddresses", "Pool has no free addresses")
+	} else {
+		setPoolCondition(&status, pool, v1alpha1.PoolConditionExhausted, false, "FreeAddresses", strconv.Itoa(status.Free)+" free addresses")
+	}
+
+	overlaps := overlappingPools(pool, pools)
+	if len(overlaps) > 0 {
+		setPoolCondition(&status, pool, v1alpha1.PoolConditionOverlapping, true, "RangeOverlaps", strings.Join(overlaps, "; "))
+	} else {
+		setPoolCondition(&status, pool, v1alpha1.PoolConditionOverlapping, false, "NoOverlap", "Pool range does not overlap with other pools")
+	}
+
+	return status
+}
+
+func setPoolCondition(status *v1alpha1.PoolStatus, pool v1alpha1.Pool, conditionType string, value bool, reason, message string) {
+	conditionStatus := metav1.ConditionFalse
+	if value {
+		conditionStatus = metav1.ConditionTrue
+	}
+
+	meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             conditionStatus,
+		ObservedGeneration: pool.Generation,
+		Reason:             reason,
+		Message:            message,
+	})
+}
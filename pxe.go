@@ -1,42 +1,102 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"net"
 	"net/http"
 	"path"
 
-	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/CRASH-Tech/dhcp-operator/cmd/kubernetes"
 	log "github.com/sirupsen/logrus"
 )
 
-func listenPXE() {
-	go func() {
-		fs := http.FileServer(http.Dir("./static/"))
+const staticDir = "./static/"
+
+// listenPXE serves boot configs and files over HTTP and, when enabled, over
+// HTTPS until the returned servers are shut down.
+func listenPXE(ctx context.Context) []*http.Server {
+	fs := http.FileServer(http.Dir(staticDir))
+
+	mux := http.NewServeMux()
+	mux.Handle("/pxe/", route("pxe", requireActiveLease(http.HandlerFunc(pxeHandler))))
+	mux.Handle("/boot", route("boot", requireActiveLease(http.HandlerFunc(bootHandler))))
+	mux.Handle("/boot/done", route("boot-done", requireActiveLease(http.HandlerFunc(bootDoneHandler)), http.MethodGet, http.MethodPost))
+	mux.Handle("/static/", route("static", http.StripPrefix("/static/", artifactHandler(fs))))
+	if config.Dns.Mode == dnsModeHosts {
+		mux.Handle("/dns/hosts", route("dns-hosts", http.HandlerFunc(hostsHandler)))
+	}
+	mux.Handle("/", route("not-found", http.NotFoundHandler()))
 
-		http.Handle("/metrics", promhttp.Handler())
-		http.HandleFunc("/pxe/", pxeHandler)
-		http.Handle("/static/", http.StripPrefix("/static/", fs))
-		err := http.ListenAndServe(fmt.Sprintf("0.0.0.0:%d", config.PxePort), nil)
-		if err != nil {
+	server := &http.Server{
+		Addr:              fmt.Sprintf("0.0.0.0:%d", config.PxePort),
+		Handler:           mux,
+		ReadHeaderTimeout: config.Http.ReadHeaderTimeout,
+		ReadTimeout:       config.Http.ReadTimeout,
+		WriteTimeout:      config.Http.WriteTimeout,
+		IdleTimeout:       config.Http.IdleTimeout,
+	}
+
+	go func() {
+		err := server.ListenAndServe()
+		if err != nil && err != http.ErrServerClosed {
 			log.Panic(err)
 		}
 	}()
+
+	servers := []*http.Server{server}
+
+	tlsServer := listenPXETLS(ctx, mux)
+	if tlsServer != nil {
+		servers = append(servers, tlsServer)
+	}
+
+	return servers
 }
 
 func pxeHandler(w http.ResponseWriter, r *http.Request) {
-	configName := path.Base(r.RequestURI)
+	configName := path.Base(r.URL.Path)
 	log.Debugf("Request PXE config: %s", configName)
 
-	pxe, err := kClient.V1alpha1().PXE().Get(configName)
+	if !requireSignedURL(w, r, configName) {
+		return
+	}
+
+	pxe, err := kClient.V1alpha1().PXE().Find(configName)
+	if kubernetes.IsNotFound(err) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("Not found!"))
+
+		return
+	}
+	if err != nil {
+		log.Error(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte("Internal error!"))
+
+		return
+	}
+	setRequestConfig(r, pxe.Name)
+
+	lease, found, err := requestLease(r)
 	if err != nil {
+		log.Error(err)
 		w.WriteHeader(http.StatusInternalServerError)
 		w.Write([]byte("Internal error!"))
 
 		return
 	}
 
-	w.WriteHeader(http.StatusOK)
-	w.Write([]byte(pxe.Spec.Data))
+	writePXE(w, r, pxe, lease, found)
+}
+
+func remoteIP(r *http.Request) net.IP {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return nil
+	}
+
+	return net.ParseIP(host)
 }
 
 // func staticHandler(w http.ResponseWriter, r *http.Request) {
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"net"
+	"net/url"
+	"strings"
+	"text/template"
+
+	"github.com/CRASH-Tech/dhcp-operator/cmd/kubernetes/api/v1alpha1"
+)
+
+// pxeTemplateData is passed to PXE templates. Lease and Pool are empty when
+// the client has no lease.
+type pxeTemplateData struct {
+	IP          string
+	MAC         string
+	Hostname    string
+	Lease       v1alpha1.Lease
+	Pool        v1alpha1.Pool
+	Labels      map[string]string
+	Query       map[string]string
+	CallbackURL string
+}
+
+// renderPXE renders the PXE data as a Go template for the client holding
+// lease, which is empty for unknown clients. The callback URL switches a host
+// booting once to local boot.
+func renderPXE(pxe v1alpha1.PXE, lease v1alpha1.Lease, query url.Values, callbackURL string) (string, error) {
+	data := pxeTemplateData{
+		IP:          lease.Spec.Ip,
+		MAC:         lease.Spec.Mac,
+		Hostname:    lease.Status.Hostname,
+		Lease:       lease,
+		Labels:      lease.Labels,
+		Query:       make(map[string]string),
+		CallbackURL: callbackURL,
+	}
+	for key := range query {
+		data.Query[key] = query.Get(key)
+	}
+
+	if lease.Spec.Pool != "" {
+		pool, err := kClient.V1alpha1().Pool().GetCached(lease.Namespace, lease.Spec.Pool)
+		if err == nil {
+			data.Pool = pool
+		}
+	}
+
+	tmpl, err := template.New(pxe.Name).Option("missingkey=zero").Parse(pxe.Spec.Data)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	err = tmpl.Execute(&b, data)
+	if err != nil {
+		return "", err
+	}
+
+	return b.String(), nil
+}
+
+// findClientLease looks up the lease of a boot client by MAC, then by SMBIOS
+// UUID and finally by its IP.
+func findClientLease(mac, uuid string, ip net.IP) (v1alpha1.Lease, bool, error) {
+	leases, err := kClient.V1alpha1().Lease().GetAllCached()
+	if err != nil {
+		return v1alpha1.Lease{}, false, err
+	}
+
+	for _, match := range []func(v1alpha1.Lease) bool{
+		func(lease v1alpha1.Lease) bool {
+			return mac != "" && strings.EqualFold(lease.Spec.Mac, mac)
+		},
+		func(lease v1alpha1.Lease) bool {
+			return uuid != "" && strings.EqualFold(lease.Spec.Uuid, uuid)
+		},
+		func(lease v1alpha1.Lease) bool {
+			return ip != nil && lease.Spec.Ip == ip.String()
+		},
+	} {
+		for _, lease := range leases {
+			if lease.DeletionTimestamp == nil && match(lease) {
+				return lease, true, nil
+			}
+		}
+	}
+
+	return v1alpha1.Lease{}, false, nil
+}
+
+func pxeContentType(pxe v1alpha1.PXE) string {
+	switch pxe.Spec.Format {
+	case v1alpha1.PXEFormatCloudInit:
+		return "text/cloud-config; charset=utf-8"
+	default:
+		return "text/plain; charset=utf-8"
+	}
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"errors"
+	"net"
+
+	"github.com/CRASH-Tech/dhcp-operator/cmd/kubernetes/api/v1alpha1"
+	"github.com/insomniacslk/dhcp/dhcpv4"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	outOfRangeAnnotation = "dhcp.xfix.org/out-of-range"
+
+	eventLeaseOutOfRange = "LeaseOutOfRange"
+)
+
+var errLeaseOutOfRange = errors.New("lease is out of pool range")
+
+// poolReconcile marks leases that do not fit their pool anymore after the
+// pool spec was changed. Their next renewal is refused by makeReply.
+func poolReconcile() {
+	log.Debug("Start pool reconcile...")
+
+	pools, err := kClient.V1alpha1().Pool().GetAllCached()
+	if err != nil {
+		log.Error(err)
+
+		return
+	}
+
+	leases, err := kClient.V1alpha1().Lease().GetAllCached()
+	if err != nil {
+		log.Error(err)
+
+		return
+	}
+
+	poolsByKey := make(map[string]v1alpha1.Pool)
+	for _, pool := range pools {
+		poolsByKey[pool.Namespace+"/"+pool.Name] = pool
+	}
+
+	for _, lease := range leases {
+		pool, found := poolsByKey[lease.Namespace+"/"+lease.Spec.Pool]
+		if !found || lease.DeletionTimestamp != nil {
+			continue
+		}
+
+		outOfRange := !isLeaseInPool(lease, pool)
+		_, marked := lease.Annotations[outOfRangeAnnotation]
+		if outOfRange == marked {
+			continue
+		}
+
+		annotations := map[string]*string{outOfRangeAnnotation: nil}
+		if outOfRange {
+			value := pool.Spec.Start + "-" + pool.Spec.End
+			annotations[outOfRangeAnnotation] = &value
+
+			log.Warnf("Lease %s is out of pool %s range %s", lease.Name, pool.Name, value)
+			leaseEvent(lease, corev1.EventTypeWarning, eventLeaseOutOfRange, "Address %s is outside of pool %s range %s", lease.Spec.Ip, pool.Name, value)
+		}
+
+		_, err := kClient.V1alpha1().Lease().SetAnnotations(lease, annotations)
+		if err != nil {
+			log.Error(err)
+
+			continue
+		}
+	}
+}
+
+// moveLease drops a lease that cannot be offered anymore, so the client gets
+// an address from another pool. Static leases are kept for the admin to fix.
+func moveLease(conn net.PacketConn, peer net.Addr, msg dhcpv4.DHCPv4, lease v1alpha1.Lease, reason error) {
+	if lease.Spec.Static {
+		log.Warnf("Cannot offer static lease %s: %s", lease.Name, reason)
+
+		return
+	}
+
+	log.Infof("Cannot offer lease %s: %s, allocate a new one", lease.Name, reason)
+	err := kClient.V1alpha1().Lease().Delete(lease)
+	if err != nil {
+		log.Error(err)
+
+		return
+	}
+
+	discover(conn, peer, msg)
+}
+
+func isLeaseInPool(lease v1alpha1.Lease, pool v1alpha1.Pool) bool {
+	ip := net.ParseIP(lease.Spec.Ip)
+	_, poolNet, err := net.ParseCIDR(pool.Spec.Subnet)
+	if err != nil || ip == nil {
+		return false
+	}
+
+	return poolNet.Contains(ip) && isIPInPool(ip, pool)
+}
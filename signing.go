@@ -0,0 +1,132 @@
+package main
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	errURLNotSigned    = errors.New("url is not signed")
+	errURLExpired      = errors.New("url is expired")
+	errURLBadSignature = errors.New("url signature mismatch")
+)
+
+// signBootFile adds an expiring token bound to the client MAC to PXE config
+// and /boot URLs. Other filenames, like TFTP paths, are returned unchanged.
+func signBootFile(filename, mac string) string {
+	if !config.Http.Signing.Enabled {
+		return filename
+	}
+
+	u, err := url.Parse(filename)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || !isSignedPath(u.Path) {
+		return filename
+	}
+
+	key, err := signingKey()
+	if err != nil {
+		log.Error(err)
+
+		return filename
+	}
+
+	expires := strconv.FormatInt(time.Now().Add(config.Http.Signing.TTL).Unix(), 10)
+
+	query := u.Query()
+	query.Set("mac", mac)
+	query.Set("expires", expires)
+	query.Set("token", urlSignature(key, mac, path.Base(u.Path), expires))
+	u.RawQuery = query.Encode()
+
+	return u.String()
+}
+
+// requireSignedURL answers 403 and returns false when signing is enabled and
+// the request has no valid token for configName.
+func requireSignedURL(w http.ResponseWriter, r *http.Request, configName string) bool {
+	if !config.Http.Signing.Enabled {
+		return true
+	}
+
+	err := verifyPXEURL(r, configName)
+	if err != nil {
+		log.Warnf("Deny %s to %s: %s", r.URL.Path, remoteIP(r), err)
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte("Forbidden!"))
+
+		return false
+	}
+
+	return true
+}
+
+// isSignedPath reports whether the path renders PXE configs, which are only
+// served with a token while signing is enabled.
+func isSignedPath(urlPath string) bool {
+	return strings.HasPrefix(urlPath, "/pxe/") || urlPath == "/boot"
+}
+
+// verifyPXEURL checks the token of a PXE config request made for configName.
+func verifyPXEURL(r *http.Request, configName string) error {
+	query := r.URL.Query()
+	mac, expires, token := query.Get("mac"), query.Get("expires"), query.Get("token")
+	if mac == "" || expires == "" || token == "" {
+		return errURLNotSigned
+	}
+
+	unix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return errURLNotSigned
+	}
+
+	if time.Now().After(time.Unix(unix, 0)) {
+		return errURLExpired
+	}
+
+	key, err := signingKey()
+	if err != nil {
+		return err
+	}
+
+	if !hmac.Equal([]byte(token), []byte(urlSignature(key, mac, configName, expires))) {
+		return errURLBadSignature
+	}
+
+	return nil
+}
+
+// urlSignature is the HMAC-SHA256 over the MAC, config name and expiry.
+func urlSignature(key []byte, mac, configName, expires string) string {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(strings.ToLower(mac) + "\n" + configName + "\n" + expires))
+
+	return base64.RawURLEncoding.EncodeToString(h.Sum(nil))
+}
+
+// signingKey is read on every use, so a rotated key file takes effect
+// without a restart.
+func signingKey() ([]byte, error) {
+	key, err := os.ReadFile(config.Http.Signing.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	key = bytes.TrimSpace(key)
+	if len(key) == 0 {
+		return nil, errors.New("signing key is empty")
+	}
+
+	return key, nil
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// withSigning enables signing with a temporary key for the test.
+func withSigning(t *testing.T, ttl time.Duration) {
+	t.Helper()
+
+	keyFile := filepath.Join(t.TempDir(), "key")
+	err := os.WriteFile(keyFile, []byte("secret\n"), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	previous := config.Http.Signing
+	config.Http.Signing.Enabled = true
+	config.Http.Signing.KeyFile = keyFile
+	config.Http.Signing.TTL = ttl
+	t.Cleanup(func() { config.Http.Signing = previous })
+}
+
+func TestSignBootFileSkipsOtherFilenames(t *testing.T) {
+	withSigning(t, time.Hour)
+
+	for _, filename := range []string{
+		"undionly.kpxe",
+		"pxe/worker",
+		"http://10.0.0.1/static/ipxe.efi",
+		"tftp://10.0.0.1/pxe/worker",
+	} {
+		got := signBootFile(filename, "aa:bb:cc:dd:ee:ff")
+		if got != filename {
+			t.Errorf("signBootFile(%q) = %q, want it unchanged", filename, got)
+		}
+	}
+}
+
+func TestSignBootFileDisabled(t *testing.T) {
+	filename := "http://10.0.0.1/pxe/worker"
+	got := signBootFile(filename, "aa:bb:cc:dd:ee:ff")
+	if got != filename {
+		t.Errorf("signBootFile(%q) = %q, want it unchanged", filename, got)
+	}
+}
+
+func TestVerifyPXEURL(t *testing.T) {
+	withSigning(t, time.Hour)
+
+	mac := "aa:bb:cc:dd:ee:ff"
+	signed, err := url.Parse(signBootFile("http://10.0.0.1:9999/pxe/worker?arch=x86", mac))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if signed.Query().Get("arch") != "x86" {
+		t.Fatalf("existing query lost: %s", signed)
+	}
+
+	signedBoot, err := url.Parse(signBootFile("http://10.0.0.1:9999/boot", mac))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expired := signed.Query()
+	expired.Set("expires", strconv.FormatInt(time.Now().Add(-time.Minute).Unix(), 10))
+
+	tests := []struct {
+		name       string
+		path       string
+		query      url.Values
+		configName string
+		want       error
+	}{
+		{"valid", "/pxe/worker", signed.Query(), "worker", nil},
+		{"valid boot", "/boot", signedBoot.Query(), "boot", nil},
+		{"not signed", "/pxe/worker", nil, "worker", errURLNotSigned},
+		{"other config", "/pxe/master", signed.Query(), "master", errURLBadSignature},
+		{"boot token on config", "/pxe/worker", signedBoot.Query(), "worker", errURLBadSignature},
+		{"other mac", "/pxe/worker", with(signed.Query(), "mac", "aa:bb:cc:dd:ee:00"), "worker", errURLBadSignature},
+		{"mac in upper case", "/pxe/worker", with(signed.Query(), "mac", "AA:BB:CC:DD:EE:FF"), "worker", nil},
+		{"extended expiry", "/pxe/worker", with(signed.Query(), "expires", strconv.FormatInt(time.Now().Add(24*time.Hour).Unix(), 10)), "worker", errURLBadSignature},
+		{"expired", "/pxe/worker", expired, "worker", errURLExpired},
+		{"invalid expiry", "/pxe/worker", with(signed.Query(), "expires", "tomorrow"), "worker", errURLNotSigned},
+		{"tampered token", "/pxe/worker", with(signed.Query(), "token", "AAAA"+signed.Query().Get("token")[4:]), "worker", errURLBadSignature},
+		{"missing token", "/pxe/worker", with(signed.Query(), "token", ""), "worker", errURLNotSigned},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", tt.path+"?"+tt.query.Encode(), nil)
+
+			err := verifyPXEURL(r, tt.configName)
+			if err != tt.want {
+				t.Errorf("verifyPXEURL() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifyPXEURLRotatedKey(t *testing.T) {
+	withSigning(t, time.Hour)
+
+	signed, err := url.Parse(signBootFile("http://10.0.0.1/pxe/worker", "aa:bb:cc:dd:ee:ff"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = os.WriteFile(config.Http.Signing.KeyFile, []byte("rotated"), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := httptest.NewRequest("GET", signed.RequestURI(), nil)
+	err = verifyPXEURL(r, "worker")
+	if err != errURLBadSignature {
+		t.Errorf("verifyPXEURL() = %v, want %v", err, errURLBadSignature)
+	}
+}
+
+func with(query url.Values, key, value string) url.Values {
+	result := url.Values{}
+	for k, v := range query {
+		result[k] = append([]string(nil), v...)
+	}
+	result.Set(key, value)
+
+	return result
+}
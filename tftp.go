@@ -0,0 +1,214 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/CRASH-Tech/dhcp-operator/cmd/kubernetes"
+	"github.com/CRASH-Tech/dhcp-operator/cmd/kubernetes/api/v1alpha1"
+	"github.com/CRASH-Tech/dhcp-operator/cmd/tftp"
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+)
+
+const tftpPxePrefix = "pxe/"
+
+var (
+	tftpTransfers = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "tftp_transfers_total",
+			Help: "The number of TFTP transfers",
+		},
+		[]string{
+			"file",
+			"result",
+		},
+	)
+
+	tftpSentBytes = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "tftp_sent_bytes_total",
+			Help: "The number of bytes sent over TFTP",
+		},
+		[]string{
+			"file",
+		},
+	)
+)
+
+// listenTFTP starts the read-only TFTP server for clients which cannot
+// fetch the first stage loader over HTTP. It returns nil when disabled.
+func listenTFTP() *tftp.Server {
+	if !config.Tftp.Enabled {
+		return nil
+	}
+
+	server := &tftp.Server{
+		Addr:        fmt.Sprintf("0.0.0.0:%d", config.Tftp.Port),
+		Read:        tftpReadHandler,
+		Transferred: tftpTransferred,
+	}
+
+	go func() {
+		log.Infof("Starting TFTP server on port %d", config.Tftp.Port)
+		err := server.ListenAndServe()
+		if err != nil && err != tftp.ErrServerClosed {
+			log.Panic(err)
+		}
+	}()
+
+	return server
+}
+
+// tftpReadHandler serves files from the static directory, files under pxe/
+// are PXE configs.
+func tftpReadHandler(filename string, remote net.Addr) (io.ReadCloser, int64, error) {
+	name := cleanTftpName(filename)
+	log.Debugf("TFTP request from %s: %s", remote, name)
+
+	clientIP := remoteUDPIP(remote)
+
+	if strings.HasPrefix(name, tftpPxePrefix) {
+		// TFTP filenames cannot carry a token, so signed configs are only
+		// served over HTTP.
+		if config.Http.Signing.Enabled {
+			return nil, 0, tftp.ErrAccessViolation
+		}
+
+		pxe, err := kClient.V1alpha1().PXE().Find(strings.TrimPrefix(name, tftpPxePrefix))
+		if kubernetes.IsNotFound(err) {
+			return nil, 0, tftp.ErrFileNotFound
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+
+		lease, found, err := findClientLease("", "", clientIP)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		if config.Http.RequireLease && (!found || !isLeaseActive(lease)) {
+			log.Warnf("Deny TFTP %s to %s, no active lease", name, clientIP)
+
+			return nil, 0, tftp.ErrAccessViolation
+		}
+
+		data := localBootScript(pxe.Spec.Format)
+		if !found || lease.Spec.Boot != v1alpha1.LeaseBootLocalboot {
+			data, err = renderPXE(pxe, lease, nil, "")
+			if err != nil {
+				return nil, 0, err
+			}
+		}
+
+		return io.NopCloser(strings.NewReader(data)), int64(len(data)), nil
+	}
+
+	artifact, cached, found := openArtifact(name)
+	if found {
+		return artifact, cached.size, nil
+	}
+
+	file, err := os.Open(filepath.Join(staticDir, filepath.FromSlash(name)))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, 0, tftp.ErrFileNotFound
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+
+		return nil, 0, err
+	}
+
+	if info.IsDir() {
+		file.Close()
+
+		return nil, 0, tftp.ErrFileNotFound
+	}
+
+	return file, info.Size(), nil
+}
+
+func tftpTransferred(filename string, remote net.Addr, sent int64, err error) {
+	result := "success"
+	switch {
+	case errors.Is(err, tftp.ErrFileNotFound):
+		result = "not_found"
+	case errors.Is(err, tftp.ErrAccessViolation):
+		result = "denied"
+	case err != nil:
+		result = "error"
+		log.Warnf("TFTP transfer of %s to %s failed: %s", filename, remote, err)
+	default:
+		log.Debugf("TFTP transfer of %s to %s done, %d bytes", filename, remote, sent)
+	}
+
+	if err == nil {
+		tftpFinishBootOnce(filename, remote)
+	}
+
+	// Missing and denied files are named by the client, so they share one
+	// label value to keep the number of series bounded. Served files are
+	// labeled by their cleaned name, so spellings of a path share a series.
+	label := cleanTftpName(filename)
+	if result == "not_found" || result == "denied" {
+		label = "unknown"
+	}
+
+	tftpTransfers.WithLabelValues(label, result).Inc()
+	tftpSentBytes.WithLabelValues(label).Add(float64(sent))
+}
+
+// tftpFinishBootOnce switches a host booting once to local boot after its
+// PXE config was transferred, a failed transfer keeps the netboot.
+func tftpFinishBootOnce(filename string, remote net.Addr) {
+	name := cleanTftpName(filename)
+	if !strings.HasPrefix(name, tftpPxePrefix) {
+		return
+	}
+
+	lease, found, err := findClientLease("", "", remoteUDPIP(remote))
+	if err != nil {
+		log.Error(err)
+
+		return
+	}
+
+	if !found || lease.Spec.Boot != v1alpha1.LeaseBootOnce {
+		return
+	}
+
+	pxe, err := kClient.V1alpha1().PXE().Find(strings.TrimPrefix(name, tftpPxePrefix))
+	if err != nil {
+		log.Error(err)
+
+		return
+	}
+
+	if !pxe.Spec.Callback {
+		finishBootOnce(lease)
+	}
+}
+
+func cleanTftpName(filename string) string {
+	return strings.TrimPrefix(path.Clean("/"+strings.ReplaceAll(filename, "\\", "/")), "/")
+}
+
+func remoteUDPIP(remote net.Addr) net.IP {
+	if addr, ok := remote.(*net.UDPAddr); ok {
+		return addr.IP
+	}
+
+	return nil
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/CRASH-Tech/dhcp-operator/cmd/kubernetes/api/v1alpha1"
+	log "github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const certificateReloadPeriod = 30 * time.Second
+
+// ipxeCipherSuites are supported by iPXE builds with HTTPS, which lack most
+// of the Go defaults for TLS 1.2.
+var ipxeCipherSuites = []uint16{
+	tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+	tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+	tls.TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA,
+	tls.TLS_ECDHE_RSA_WITH_AES_256_CBC_SHA,
+	tls.TLS_RSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_RSA_WITH_AES_256_GCM_SHA384,
+	tls.TLS_RSA_WITH_AES_128_CBC_SHA256,
+	tls.TLS_RSA_WITH_AES_128_CBC_SHA,
+	tls.TLS_RSA_WITH_AES_256_CBC_SHA,
+}
+
+// certificateStore keeps the certificate from a Secret, which is reloaded
+// without restarting the server.
+type certificateStore struct {
+	sync.RWMutex
+	certificate     *tls.Certificate
+	resourceVersion string
+}
+
+func (store *certificateStore) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	store.RLock()
+	defer store.RUnlock()
+
+	if store.certificate == nil {
+		return nil, fmt.Errorf("certificate from secret %s is not loaded", config.Http.Tls.SecretName)
+	}
+
+	return store.certificate, nil
+}
+
+// reload reads the Secret. Intermediate certificates in ca.crt are appended
+// to the chain, as iPXE does not fetch missing intermediates.
+func (store *certificateStore) reload(ctx context.Context) error {
+	secret, err := config.KubernetesClient.CoreV1().Secrets(getTlsNamespace()).Get(ctx, config.Http.Tls.SecretName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	store.RLock()
+	unchanged := store.resourceVersion == secret.ResourceVersion
+	store.RUnlock()
+	if unchanged {
+		return nil
+	}
+
+	chain := secret.Data["tls.crt"]
+	if ca, ok := secret.Data["ca.crt"]; ok {
+		chain = append(append(append([]byte{}, chain...), '\n'), ca...)
+	}
+
+	certificate, err := tls.X509KeyPair(chain, secret.Data["tls.key"])
+	if err != nil {
+		return fmt.Errorf("invalid certificate in secret %s: %w", secret.Name, err)
+	}
+
+	store.Lock()
+	defer store.Unlock()
+
+	store.certificate = &certificate
+	store.resourceVersion = secret.ResourceVersion
+	log.Infof("Loaded TLS certificate from secret %s", secret.Name)
+
+	return nil
+}
+
+func (store *certificateStore) run(ctx context.Context) {
+	ticker := time.NewTicker(certificateReloadPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := store.reload(ctx)
+			if err != nil {
+				log.Error(err)
+			}
+		}
+	}
+}
+
+// listenPXETLS serves the handler over HTTPS with the certificate from the
+// configured Secret. It returns nil when TLS is disabled.
+func listenPXETLS(ctx context.Context, handler http.Handler) *http.Server {
+	if !config.Http.Tls.Enabled {
+		return nil
+	}
+
+	store := &certificateStore{}
+	err := store.reload(ctx)
+	if err != nil {
+		log.Error(err)
+	}
+	go store.run(ctx)
+
+	server := &http.Server{
+		Addr:              fmt.Sprintf("0.0.0.0:%d", config.Http.Tls.Port),
+		Handler:           handler,
+		ReadHeaderTimeout: config.Http.ReadHeaderTimeout,
+		ReadTimeout:       config.Http.ReadTimeout,
+		WriteTimeout:      config.Http.WriteTimeout,
+		IdleTimeout:       config.Http.IdleTimeout,
+		TLSConfig: &tls.Config{
+			GetCertificate: store.GetCertificate,
+			MinVersion:     tls.VersionTLS12,
+			CipherSuites:   ipxeCipherSuites,
+		},
+	}
+
+	go func() {
+		log.Infof("Starting HTTPS PXE server on port %d", config.Http.Tls.Port)
+		err := server.ListenAndServeTLS("", "")
+		if err != nil && err != http.ErrServerClosed {
+			log.Panic(err)
+		}
+	}()
+
+	return server
+}
+
+// requireActiveLease restricts the handler to clients whose address holds an
+// active lease, so random hosts cannot pull install secrets.
+func requireActiveLease(handler http.Handler) http.Handler {
+	if !config.Http.RequireLease {
+		return handler
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ip := remoteIP(r)
+
+		lease, found, err := findClientLease("", "", ip)
+		if err != nil {
+			log.Error(err)
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte("Internal error!"))
+
+			return
+		}
+
+		if !found || !isLeaseActive(lease) {
+			log.Warnf("Deny %s from %s, no active lease", r.URL.Path, ip)
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte("Forbidden!"))
+
+			return
+		}
+
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// requestLease finds the lease of the caller. With requireLease only the
+// caller address is trusted, so a host cannot pick another host's lease by
+// the mac or uuid parameters.
+func requestLease(r *http.Request) (v1alpha1.Lease, bool, error) {
+	if config.Http.RequireLease {
+		return findClientLease("", "", remoteIP(r))
+	}
+
+	query := r.URL.Query()
+
+	return findClientLease(query.Get("mac"), query.Get("uuid"), remoteIP(r))
+}
+
+func getTlsNamespace() string {
+	if config.Http.Tls.SecretNamespace != "" {
+		return config.Http.Tls.SecretNamespace
+	}
+
+	return namespace
+}
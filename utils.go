@@ -13,7 +13,7 @@ import (
 )
 
 func readConfig(path string) (common.Config, error) {
-	config := common.Config{}
+	config := common.NewConfig()
 
 	yamlFile, err := ioutil.ReadFile(path)
 	if err != nil {
@@ -36,6 +36,10 @@ func getAvialablePools(ip net.IP, requested bool) ([]v1alpha1.Pool, error) {
 	}
 
 	for _, pool := range pools {
+		if isPoolDraining(pool) {
+			continue
+		}
+
 		_, poolNet, err := net.ParseCIDR(pool.Spec.Subnet)
 		if err != nil {
 			log.Error(err)
@@ -157,3 +161,39 @@ func getAvialableIPs(pool v1alpha1.Pool, requestedIP net.IP, requested bool) ([]
 
 	return result, nil
 }
+
+// poolSize returns the number of host addresses between Start and End that
+// belong to the pool subnet.
+func poolSize(pool v1alpha1.Pool) int {
+	_, poolNet, err := net.ParseCIDR(pool.Spec.Subnet)
+	if err != nil {
+		return 0
+	}
+
+	start := net.ParseIP(pool.Spec.Start).To4()
+	end := net.ParseIP(pool.Spec.End).To4()
+	network := poolNet.IP.To4()
+	if start == nil || end == nil || network == nil {
+		return 0
+	}
+
+	mask := binary.BigEndian.Uint32(poolNet.Mask)
+	first := binary.BigEndian.Uint32(network) + 1
+	last := (first - 1) | (mask ^ 0xffffffff) - 1
+
+	from := binary.BigEndian.Uint32(start)
+	if from < first {
+		from = first
+	}
+
+	to := binary.BigEndian.Uint32(end)
+	if to > last {
+		to = last
+	}
+
+	if to < from {
+		return 0
+	}
+
+	return int(to-from) + 1
+}
@@ -0,0 +1,272 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/CRASH-Tech/dhcp-operator/cmd/kubernetes/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+func validatePool(pool v1alpha1.Pool, pools []v1alpha1.Pool) []string {
+	var result []string
+
+	// A deleted pool must stay editable, so its finalizer can be removed.
+	if pool.DeletionTimestamp != nil {
+		return result
+	}
+
+	_, poolNet, err := net.ParseCIDR(pool.Spec.Subnet)
+	if err != nil {
+		result = append(result, fmt.Sprintf("spec.subnet: invalid CIDR %q", pool.Spec.Subnet))
+	}
+
+	start := net.ParseIP(pool.Spec.Start).To4()
+	if start == nil {
+		result = append(result, fmt.Sprintf("spec.start: invalid IP %q", pool.Spec.Start))
+	}
+
+	end := net.ParseIP(pool.Spec.End).To4()
+	if end == nil {
+		result = append(result, fmt.Sprintf("spec.end: invalid IP %q", pool.Spec.End))
+	}
+
+	if poolNet != nil && start != nil && !poolNet.Contains(start) {
+		result = append(result, fmt.Sprintf("spec.start: %s is outside of subnet %s", start, poolNet))
+	}
+
+	if poolNet != nil && end != nil && !poolNet.Contains(end) {
+		result = append(result, fmt.Sprintf("spec.end: %s is outside of subnet %s", end, poolNet))
+	}
+
+	if start != nil && end != nil && bytes.Compare(start, end) > 0 {
+		result = append(result, fmt.Sprintf("spec.start: %s is after spec.end %s", start, end))
+	}
+
+	_, err = time.ParseDuration(pool.Spec.Lease)
+	if err != nil {
+		result = append(result, fmt.Sprintf("spec.lease: invalid duration %q", pool.Spec.Lease))
+	}
+
+	for _, ip := range []struct {
+		field string
+		value string
+	}{
+		{"spec.routers", pool.Spec.Routers},
+		{"spec.broadcast", pool.Spec.Broadcast},
+	} {
+		if ip.value != "" && net.ParseIP(ip.value) == nil {
+			result = append(result, fmt.Sprintf("%s: invalid IP %q", ip.field, ip.value))
+		}
+	}
+
+	if pool.Spec.HttpFilename != "" && !isURLFilename(pool.Spec.HttpFilename) {
+		result = append(result, fmt.Sprintf("spec.httpFilename: invalid URL %q", pool.Spec.HttpFilename))
+	}
+
+	for i, srv := range pool.Spec.Dns {
+		if net.ParseIP(srv) == nil {
+			result = append(result, fmt.Sprintf("spec.dns[%d]: invalid IP %q", i, srv))
+		}
+	}
+
+	for i, srv := range pool.Spec.Ntp {
+		if net.ParseIP(srv) == nil {
+			result = append(result, fmt.Sprintf("spec.ntp[%d]: invalid IP %q", i, srv))
+		}
+	}
+
+	result = append(result, overlappingPools(pool, pools)...)
+
+	return result
+}
+
+// overlappingPools describes every pool in the same namespace whose range
+// overlaps with the range of pool.
+func overlappingPools(pool v1alpha1.Pool, pools []v1alpha1.Pool) []string {
+	var result []string
+
+	start := net.ParseIP(pool.Spec.Start).To4()
+	end := net.ParseIP(pool.Spec.End).To4()
+	if start == nil || end == nil {
+		return result
+	}
+
+	for _, p := range pools {
+		if p.Namespace != pool.Namespace || p.Name == pool.Name {
+			continue
+		}
+
+		pStart := net.ParseIP(p.Spec.Start).To4()
+		pEnd := net.ParseIP(p.Spec.End).To4()
+		if pStart == nil || pEnd == nil {
+			continue
+		}
+
+		if bytes.Compare(start, pEnd) <= 0 && bytes.Compare(pStart, end) <= 0 {
+			result = append(result, fmt.Sprintf("spec: range %s-%s overlaps with pool %s (%s-%s)", start, end, p.Name, pStart, pEnd))
+		}
+	}
+
+	return result
+}
+
+// validateLease checks a lease, old is the stored lease on update. An update
+// which keeps the address and pool skips the pool range check, so the
+// operator can still annotate and patch a lease left out of range.
+func validateLease(lease v1alpha1.Lease, old *v1alpha1.Lease, leases []v1alpha1.Lease, pools []v1alpha1.Pool) []string {
+	var result []string
+
+	// A deleted lease must stay editable, so its finalizer can be removed.
+	if lease.DeletionTimestamp != nil {
+		return result
+	}
+
+	ip := net.ParseIP(lease.Spec.Ip).To4()
+	if ip == nil || ip.Equal(net.IPv4zero) {
+		result = append(result, fmt.Sprintf("spec.ip: invalid IP %q", lease.Spec.Ip))
+	}
+
+	_, err := net.ParseMAC(lease.Spec.Mac)
+	if err != nil {
+		result = append(result, fmt.Sprintf("spec.mac: invalid MAC %q", lease.Spec.Mac))
+	}
+
+	switch lease.Spec.Boot {
+	case "", v1alpha1.LeaseBootNetboot, v1alpha1.LeaseBootLocalboot, v1alpha1.LeaseBootOnce:
+	default:
+		result = append(result, fmt.Sprintf("spec.boot: unsupported boot mode %q", lease.Spec.Boot))
+	}
+
+	for _, l := range leases {
+		if l.Namespace == lease.Namespace && l.Name == lease.Name || l.DeletionTimestamp != nil {
+			continue
+		}
+
+		if lease.Spec.Mac != "" && strings.EqualFold(l.Spec.Mac, lease.Spec.Mac) {
+			result = append(result, fmt.Sprintf("spec.mac: %s is already used by lease %s", lease.Spec.Mac, l.Name))
+		}
+	}
+
+	if ip == nil {
+		return result
+	}
+
+	if old != nil && old.Spec.Ip == lease.Spec.Ip && old.Spec.Pool == lease.Spec.Pool {
+		return result
+	}
+
+	var inPool bool
+	for _, pool := range pools {
+		if pool.Namespace != lease.Namespace {
+			continue
+		}
+
+		if lease.Spec.Pool != "" && pool.Name != lease.Spec.Pool {
+			continue
+		}
+
+		if isIPInPool(ip, pool) {
+			inPool = true
+
+			break
+		}
+	}
+
+	if !inPool {
+		if lease.Spec.Pool != "" {
+			result = append(result, fmt.Sprintf("spec.ip: %s is outside of pool %s", ip, lease.Spec.Pool))
+		} else {
+			result = append(result, fmt.Sprintf("spec.ip: %s is outside of any pool", ip))
+		}
+	}
+
+	return result
+}
+
+func validatePXE(pxe v1alpha1.PXE) []string {
+	var result []string
+
+	if strings.TrimSpace(pxe.Spec.Data) == "" {
+		result = append(result, "spec.data: must not be empty")
+	}
+
+	_, err := template.New(pxe.Name).Parse(pxe.Spec.Data)
+	if err != nil {
+		result = append(result, fmt.Sprintf("spec.data: invalid template: %s", err))
+	}
+
+	if pxe.Spec.Selector != nil {
+		_, err := metav1.LabelSelectorAsSelector(pxe.Spec.Selector)
+		if err != nil {
+			result = append(result, fmt.Sprintf("spec.selector: %s", err))
+		}
+	}
+
+	switch pxe.Spec.Format {
+	case "", v1alpha1.PXEFormatIPXE, v1alpha1.PXEFormatGrub, v1alpha1.PXEFormatPXELinux, v1alpha1.PXEFormatCloudInit:
+	default:
+		result = append(result, fmt.Sprintf("spec.format: unsupported format %q", pxe.Spec.Format))
+	}
+
+	return result
+}
+
+func validateBootArtifact(artifact v1alpha1.BootArtifact, artifacts []v1alpha1.BootArtifact) []string {
+	var result []string
+
+	if cleanArtifactPath(artifact.Spec.Path) == "" {
+		result = append(result, "spec.path: must not be empty")
+	}
+
+	var sources int
+	if artifact.Spec.URL != "" {
+		sources++
+	}
+	if artifact.Spec.ConfigMap != nil {
+		sources++
+	}
+	if artifact.Spec.PersistentVolumeClaim != nil {
+		sources++
+	}
+	if sources != 1 {
+		result = append(result, "spec: exactly one of url, configMap and persistentVolumeClaim must be set")
+	}
+
+	if pvc := artifact.Spec.PersistentVolumeClaim; pvc != nil {
+		for _, msg := range validation.IsDNS1123Subdomain(pvc.ClaimName) {
+			result = append(result, fmt.Sprintf("spec.persistentVolumeClaim.claimName: %s", msg))
+		}
+	}
+
+	// In namespaced scope a tenant may only read ConfigMaps of its own
+	// namespace.
+	if cm := artifact.Spec.ConfigMap; cm != nil && config.Scope == scopeNamespaced && cm.Namespace != "" && cm.Namespace != artifact.Namespace {
+		result = append(result, fmt.Sprintf("spec.configMap.namespace: must be %s in namespaced scope", artifact.Namespace))
+	}
+
+	checksum := strings.TrimPrefix(artifact.Spec.Checksum, artifactChecksumPrefix)
+	_, err := hex.DecodeString(checksum)
+	if artifact.Spec.Checksum != "" && (err != nil || len(checksum) != sha256.Size*2) {
+		result = append(result, fmt.Sprintf("spec.checksum: invalid sha256 %q", artifact.Spec.Checksum))
+	}
+
+	for _, a := range artifacts {
+		if a.Namespace == artifact.Namespace && a.Name == artifact.Name {
+			continue
+		}
+
+		if cleanArtifactPath(a.Spec.Path) == cleanArtifactPath(artifact.Spec.Path) {
+			result = append(result, fmt.Sprintf("spec.path: %s is already served by %s", artifact.Spec.Path, a.Name))
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/CRASH-Tech/dhcp-operator/cmd/kubernetes/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func testPool(name, start, end string) v1alpha1.Pool {
+	pool := v1alpha1.Pool{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	pool.Spec.Subnet = "10.0.0.0/24"
+	pool.Spec.Start = start
+	pool.Spec.End = end
+	pool.Spec.Lease = "1h"
+
+	return pool
+}
+
+func testLease(name, ip, mac string) v1alpha1.Lease {
+	lease := v1alpha1.Lease{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	lease.Spec.Ip = ip
+	lease.Spec.Mac = mac
+	lease.Spec.Pool = "pool-a"
+
+	return lease
+}
+
+// expectErrors checks that every wanted message is reported, or that none is
+// reported when nothing is wanted.
+func expectErrors(t *testing.T, got []string, want []string) {
+	t.Helper()
+
+	if len(want) == 0 && len(got) > 0 {
+		t.Fatalf("expected no errors, got %q", got)
+	}
+
+	for _, w := range want {
+		var found bool
+		for _, g := range got {
+			if strings.Contains(g, w) {
+				found = true
+
+				break
+			}
+		}
+
+		if !found {
+			t.Errorf("expected an error containing %q, got %q", w, got)
+		}
+	}
+}
+
+func TestValidatePool(t *testing.T) {
+	existing := []v1alpha1.Pool{
+		testPool("pool-a", "10.0.0.10", "10.0.0.20"),
+	}
+
+	tests := []struct {
+		name string
+		pool v1alpha1.Pool
+		want []string
+	}{
+		{"valid", testPool("pool-b", "10.0.0.21", "10.0.0.30"), nil},
+		{"update of itself", testPool("pool-a", "10.0.0.10", "10.0.0.25"), nil},
+		{"overlaps at the end", testPool("pool-b", "10.0.0.20", "10.0.0.30"), []string{"overlaps with pool pool-a"}},
+		{"overlaps at the start", testPool("pool-b", "10.0.0.1", "10.0.0.10"), []string{"overlaps with pool pool-a"}},
+		{"contains other pool", testPool("pool-b", "10.0.0.1", "10.0.0.100"), []string{"overlaps with pool pool-a"}},
+		{"inside other pool", testPool("pool-b", "10.0.0.12", "10.0.0.13"), []string{"overlaps with pool pool-a"}},
+		{"start after end", testPool("pool-b", "10.0.0.30", "10.0.0.21"), []string{"is after spec.end"}},
+		{"outside of subnet", testPool("pool-b", "10.0.1.1", "10.0.1.10"), []string{"spec.start", "spec.end"}},
+		{"invalid start", testPool("pool-b", "10.0.0", "10.0.0.30"), []string{"spec.start: invalid IP"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expectErrors(t, validatePool(tt.pool, existing), tt.want)
+		})
+	}
+
+	t.Run("other namespace", func(t *testing.T) {
+		pool := testPool("pool-b", "10.0.0.10", "10.0.0.20")
+		pool.Namespace = "other"
+
+		expectErrors(t, validatePool(pool, existing), nil)
+	})
+
+	t.Run("invalid fields", func(t *testing.T) {
+		pool := testPool("pool-b", "10.0.0.21", "10.0.0.30")
+		pool.Spec.Subnet = "10.0.0.0"
+		pool.Spec.Lease = "forever"
+		pool.Spec.Routers = "gateway"
+		pool.Spec.Dns = []string{"8.8.8"}
+
+		expectErrors(t, validatePool(pool, existing), []string{"spec.subnet", "spec.lease", "spec.routers", "spec.dns[0]"})
+	})
+}
+
+func TestValidateLease(t *testing.T) {
+	pools := []v1alpha1.Pool{
+		testPool("pool-a", "10.0.0.10", "10.0.0.20"),
+	}
+	leases := []v1alpha1.Lease{
+		testLease("lease-a", "10.0.0.10", "aa:bb:cc:dd:ee:01"),
+		testLease("lease-b", "10.0.0.11", "aa:bb:cc:dd:ee:02"),
+	}
+	outOfRange := testLease("lease-c", "10.0.0.50", "aa:bb:cc:dd:ee:03")
+
+	tests := []struct {
+		name  string
+		lease v1alpha1.Lease
+		old   *v1alpha1.Lease
+		want  []string
+	}{
+		{"valid create", testLease("lease-c", "10.0.0.12", "aa:bb:cc:dd:ee:03"), nil, nil},
+		{"duplicate mac on create", testLease("lease-c", "10.0.0.12", "AA:BB:CC:DD:EE:01"), nil, []string{"already used by lease lease-a"}},
+		{"outside of pool on create", outOfRange, nil, []string{"outside of pool pool-a"}},
+		{"unknown pool", func() v1alpha1.Lease {
+			lease := testLease("lease-c", "10.0.0.12", "aa:bb:cc:dd:ee:03")
+			lease.Spec.Pool = "pool-x"
+
+			return lease
+		}(), nil, []string{"outside of pool pool-x"}},
+		{"invalid ip", testLease("lease-c", "0.0.0.0", "aa:bb:cc:dd:ee:03"), nil, []string{"spec.ip: invalid IP"}},
+		{"invalid mac", testLease("lease-c", "10.0.0.12", "aa:bb"), nil, []string{"spec.mac: invalid MAC"}},
+		{"update of itself", leases[0], &leases[0], nil},
+		{"duplicate mac on update", testLease("lease-a", "10.0.0.10", "aa:bb:cc:dd:ee:02"), &leases[0], []string{"already used by lease lease-b"}},
+		{"update keeps out of range lease", outOfRange, &outOfRange, nil},
+		{"update moves into another range", testLease("lease-a", "10.0.0.50", "aa:bb:cc:dd:ee:01"), &leases[0], []string{"outside of pool pool-a"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expectErrors(t, validateLease(tt.lease, tt.old, leases, pools), tt.want)
+		})
+	}
+
+	t.Run("deleted lease is not checked", func(t *testing.T) {
+		lease := testLease("lease-c", "10.0.0.50", "aa:bb:cc:dd:ee:01")
+		now := metav1.Now()
+		lease.DeletionTimestamp = &now
+
+		expectErrors(t, validateLease(lease, nil, leases, pools), nil)
+	})
+
+	t.Run("unsupported boot mode", func(t *testing.T) {
+		lease := testLease("lease-c", "10.0.0.12", "aa:bb:cc:dd:ee:03")
+		lease.Spec.Boot = "sometimes"
+
+		expectErrors(t, validateLease(lease, nil, leases, pools), []string{"spec.boot"})
+	})
+}
@@ -0,0 +1,232 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/CRASH-Tech/dhcp-operator/cmd/kubernetes/api/v1alpha1"
+	log "github.com/sirupsen/logrus"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// listenWebhook serves admission webhooks on every replica, independently of
+// the leader election, until ctx is cancelled.
+func listenWebhook(ctx context.Context) {
+	if !config.Webhook.Enabled {
+		return
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/validate", admissionHandler(validate))
+	mux.HandleFunc("/mutate", admissionHandler(mutate))
+
+	server := &http.Server{
+		Addr:    fmt.Sprintf("0.0.0.0:%d", config.Webhook.Port),
+		Handler: mux,
+	}
+
+	go func() {
+		<-ctx.Done()
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		server.Shutdown(shutdownCtx)
+	}()
+
+	go func() {
+		log.Infof("Starting admission webhook on port %d", config.Webhook.Port)
+		err := server.ListenAndServeTLS(config.Webhook.CertFile, config.Webhook.KeyFile)
+		if err != nil && err != http.ErrServerClosed {
+			log.Fatal(err)
+		}
+	}()
+}
+
+type admitFunc func(request *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse
+
+func admissionHandler(admit admitFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+
+			return
+		}
+
+		var review admissionv1.AdmissionReview
+		err = json.Unmarshal(body, &review)
+		if err != nil || review.Request == nil {
+			http.Error(w, "invalid admission review", http.StatusBadRequest)
+
+			return
+		}
+
+		response := admit(review.Request)
+		response.UID = review.Request.UID
+		review.Response = response
+		review.Request = nil
+
+		data, err := json.Marshal(review)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(data)
+	}
+}
+
+func validate(request *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	if request.Operation == admissionv1.Delete {
+		return allowed()
+	}
+
+	var errs []string
+	switch request.Kind.Kind {
+	case "Pool":
+		var pool v1alpha1.Pool
+		err := json.Unmarshal(request.Object.Raw, &pool)
+		if err != nil {
+			return denied(err.Error())
+		}
+
+		pools, err := kClient.V1alpha1().Pool().GetAllCached()
+		if err != nil {
+			return denied(err.Error())
+		}
+
+		errs = validatePool(pool, pools)
+
+	case "Lease":
+		var lease v1alpha1.Lease
+		err := json.Unmarshal(request.Object.Raw, &lease)
+		if err != nil {
+			return denied(err.Error())
+		}
+
+		var old *v1alpha1.Lease
+		if request.Operation == admissionv1.Update {
+			old = &v1alpha1.Lease{}
+			err = json.Unmarshal(request.OldObject.Raw, old)
+			if err != nil {
+				return denied(err.Error())
+			}
+		}
+
+		leases, err := kClient.V1alpha1().Lease().GetAllCached()
+		if err != nil {
+			return denied(err.Error())
+		}
+
+		pools, err := kClient.V1alpha1().Pool().GetAllCached()
+		if err != nil {
+			return denied(err.Error())
+		}
+
+		errs = validateLease(lease, old, leases, pools)
+
+	case "PXE":
+		var pxe v1alpha1.PXE
+		err := json.Unmarshal(request.Object.Raw, &pxe)
+		if err != nil {
+			return denied(err.Error())
+		}
+
+		errs = validatePXE(pxe)
+
+	case "BootArtifact":
+		var artifact v1alpha1.BootArtifact
+		err := json.Unmarshal(request.Object.Raw, &artifact)
+		if err != nil {
+			return denied(err.Error())
+		}
+
+		artifacts, err := kClient.V1alpha1().BootArtifact().GetAll()
+		if err != nil {
+			return denied(err.Error())
+		}
+
+		errs = validateBootArtifact(artifact, artifacts)
+	}
+
+	if len(errs) > 0 {
+		log.Warnf("Rejected %s %s: %s", request.Kind.Kind, request.Name, strings.Join(errs, "; "))
+
+		return denied(strings.Join(errs, "; "))
+	}
+
+	return allowed()
+}
+
+func mutate(request *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	if request.Operation != admissionv1.Create && request.Operation != admissionv1.Update {
+		return allowed()
+	}
+
+	var patch []patchOperation
+	switch request.Kind.Kind {
+	case "Pool":
+		var pool v1alpha1.Pool
+		err := json.Unmarshal(request.Object.Raw, &pool)
+		if err != nil {
+			return denied(err.Error())
+		}
+
+		patch = defaultPool(pool)
+
+	case "Lease":
+		var lease v1alpha1.Lease
+		err := json.Unmarshal(request.Object.Raw, &lease)
+		if err != nil {
+			return denied(err.Error())
+		}
+
+		pools, err := kClient.V1alpha1().Pool().GetAllCached()
+		if err != nil {
+			return denied(err.Error())
+		}
+
+		patch = defaultLease(lease, pools)
+	}
+
+	if len(patch) == 0 {
+		return allowed()
+	}
+
+	data, err := json.Marshal(patch)
+	if err != nil {
+		return denied(err.Error())
+	}
+
+	patchType := admissionv1.PatchTypeJSONPatch
+	response := allowed()
+	response.Patch = data
+	response.PatchType = &patchType
+
+	return response
+}
+
+func allowed() *admissionv1.AdmissionResponse {
+	return &admissionv1.AdmissionResponse{Allowed: true}
+}
+
+func denied(message string) *admissionv1.AdmissionResponse {
+	return &admissionv1.AdmissionResponse{
+		Allowed: false,
+		Result: &metav1.Status{
+			Status:  metav1.StatusFailure,
+			Message: message,
+			Reason:  metav1.StatusReasonInvalid,
+			Code:    http.StatusUnprocessableEntity,
+		},
+	}
+}